@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"forumApp/configs"
 	"forumApp/internal/forumapp/app/delivery"
 	"forumApp/internal/forumapp/app/repository"
@@ -24,7 +25,33 @@ func main() {
 
 	timeoutContext := configs.Timeouts.ContextTimeout
 
-	usecase := usecase.NewUserUsecase(repo, timeoutContext)
+	events := usecase.NewEventBroker(repo)
+	go events.Run(context.Background())
+
+	webhooks := usecase.NewWebhookWorker(repo, configs.Webhooks.PollInterval, configs.Webhooks.Timeout, configs.Webhooks.MaxAttempts)
+	go webhooks.Run(context.Background())
+
+	bus := usecase.NewEventBus()
+	var sinks []usecase.OutboxSink
+	for _, sink := range configs.Outbox.Sinks {
+		switch sink {
+		case "log":
+			sinks = append(sinks, usecase.LogSink{})
+		case "webhook":
+			sinks = append(sinks, usecase.NewWebhookSink(configs.Outbox.WebhookUrl, configs.Outbox.WebhookSecret))
+		case "bus":
+			sinks = append(sinks, bus)
+		default:
+			log.Fatalf("Unknown outbox sink %s", sink)
+		}
+	}
+	outbox := usecase.NewOutboxDispatcher(repo, configs.Outbox.PollInterval, sinks...)
+	go outbox.Run(context.Background())
+
+	trending := usecase.NewTrendingJob(repo, configs.Trending.Interval, configs.Trending.Windows)
+	go trending.Run(context.Background())
+
+	usecase := usecase.NewUserUsecase(repo, events, trending, configs.Reactions.Allowed, timeoutContext)
 
 	delivery.SetUserRouting(router, usecase)
 
@@ -21,13 +21,47 @@ type TimeoutsConfig struct {
 	ContextTimeout time.Duration
 }
 
+type WebhooksConfig struct {
+	PollInterval time.Duration
+	Timeout      time.Duration
+	MaxAttempts  int
+}
+
+type ReactionsConfig struct {
+	Allowed []string
+}
+
+type TrendingConfig struct {
+	Interval time.Duration
+	Windows  map[string]time.Duration
+}
+
+type OutboxConfig struct {
+	PollInterval  time.Duration
+	Sinks         []string
+	WebhookUrl    string
+	WebhookSecret string
+}
+
 var (
-	Postgres PostgresConfig
-	Timeouts TimeoutsConfig
+	Postgres  PostgresConfig
+	Timeouts  TimeoutsConfig
+	Webhooks  WebhooksConfig
+	Outbox    OutboxConfig
+	Reactions ReactionsConfig
+	Trending  TrendingConfig
 )
 
 func SetConfig() {
 	viper.SetConfigFile("config.json")
+	viper.SetDefault(`webhooks.poll_interval`, "1s")
+	viper.SetDefault(`webhooks.timeout`, "10s")
+	viper.SetDefault(`webhooks.max_attempts`, 8)
+	viper.SetDefault(`outbox.poll_interval`, "1s")
+	viper.SetDefault(`outbox.sinks`, []string{"log"})
+	viper.SetDefault(`reactions.allowed`, []string{"👍", "👎", "😄", "🎉", "😕", "❤️", "🚀", "👀"})
+	viper.SetDefault(`trending.interval`, "1m")
+	viper.SetDefault(`trending.windows`, map[string]string{"1h": "1h", "24h": "24h", "7d": "168h"})
 	err := viper.ReadInConfig()
 	if err != nil {
 		log.Fatal(err)
@@ -46,4 +80,32 @@ func SetConfig() {
 		ReadTimeout:    15 * time.Second,
 		ContextTimeout: time.Second * 2,
 	}
+
+	Webhooks = WebhooksConfig{
+		PollInterval: viper.GetDuration(`webhooks.poll_interval`),
+		Timeout:      viper.GetDuration(`webhooks.timeout`),
+		MaxAttempts:  viper.GetInt(`webhooks.max_attempts`),
+	}
+
+	Outbox = OutboxConfig{
+		PollInterval:  viper.GetDuration(`outbox.poll_interval`),
+		Sinks:         viper.GetStringSlice(`outbox.sinks`),
+		WebhookUrl:    viper.GetString(`outbox.webhook_url`),
+		WebhookSecret: viper.GetString(`outbox.webhook_secret`),
+	}
+
+	Reactions = ReactionsConfig{
+		Allowed: viper.GetStringSlice(`reactions.allowed`),
+	}
+
+	Trending = TrendingConfig{
+		Interval: viper.GetDuration(`trending.interval`),
+		Windows:  make(map[string]time.Duration),
+	}
+	for name, window := range viper.GetStringMapString(`trending.windows`) {
+		Trending.Windows[name], err = time.ParseDuration(window)
+		if err != nil {
+			log.Fatalf("Invalid trending window %s: %s", name, err)
+		}
+	}
 }
@@ -1,31 +1,128 @@
 SET SYNCHRONOUS_COMMIT = 'off';
 CREATE EXTENSION IF NOT EXISTS CITEXT;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
 DROP TABLE IF EXISTS users CASCADE;
 DROP TABLE IF EXISTS forums CASCADE;
 DROP TABLE IF EXISTS threads CASCADE;
 DROP TABLE IF EXISTS votes CASCADE;
 DROP TABLE IF EXISTS posts CASCADE;
 DROP TABLE IF EXISTS forum_users CASCADE;
+DROP TABLE IF EXISTS forum_settings CASCADE;
+DROP TABLE IF EXISTS mentions CASCADE;
+DROP TABLE IF EXISTS notifications CASCADE;
+DROP TABLE IF EXISTS notification_preferences CASCADE;
+DROP TABLE IF EXISTS thread_subscriptions CASCADE;
+DROP TABLE IF EXISTS forum_subscriptions CASCADE;
+DROP TABLE IF EXISTS feed_cursors CASCADE;
+DROP TABLE IF EXISTS thread_reads CASCADE;
+DROP TABLE IF EXISTS thread_read_posts CASCADE;
+DROP TABLE IF EXISTS stream_events CASCADE;
+DROP TABLE IF EXISTS webhooks CASCADE;
+DROP TABLE IF EXISTS webhook_deliveries CASCADE;
+DROP TABLE IF EXISTS outbox CASCADE;
+DROP TABLE IF EXISTS post_votes CASCADE;
+DROP TABLE IF EXISTS post_reactions CASCADE;
+DROP TABLE IF EXISTS user_reputation CASCADE;
+DROP TABLE IF EXISTS forum_daily_stats CASCADE;
+DROP TABLE IF EXISTS forum_user_daily_stats CASCADE;
+DROP TABLE IF EXISTS forum_user_stats CASCADE;
+DROP TABLE IF EXISTS user_stats CASCADE;
+DROP TABLE IF EXISTS thread_daily_votes CASCADE;
+DROP TABLE IF EXISTS trending_threads CASCADE;
 DROP FUNCTION IF EXISTS update_thread_votes_after_insert();
 DROP FUNCTION IF EXISTS update_thread_votes_after_update();
+DROP FUNCTION IF EXISTS update_thread_votes_after_delete();
+DROP FUNCTION IF EXISTS update_post_votes();
+DROP FUNCTION IF EXISTS update_post_reactions();
+DROP FUNCTION IF EXISTS decayed_reputation(DOUBLE PRECISION, TIMESTAMPTZ);
+DROP FUNCTION IF EXISTS add_reputation(CITEXT, CITEXT, BIGINT, INT);
+DROP FUNCTION IF EXISTS update_thread_author_reputation();
+DROP FUNCTION IF EXISTS update_post_author_reputation();
+DROP FUNCTION IF EXISTS add_forum_activity(CITEXT, CITEXT, DATE, INT, INT);
+DROP FUNCTION IF EXISTS update_post_activity();
+DROP FUNCTION IF EXISTS update_thread_activity();
+DROP FUNCTION IF EXISTS add_thread_votes(BIGINT, DATE, INT);
+DROP FUNCTION IF EXISTS update_thread_daily_votes();
+DROP FUNCTION IF EXISTS move_thread_daily_votes();
 DROP FUNCTION IF EXISTS insert_forum_users();
+DROP FUNCTION IF EXISTS thread_hot(INT, INT, TIMESTAMPTZ);
+DROP FUNCTION IF EXISTS update_thread_search_vector();
+DROP FUNCTION IF EXISTS add_post_stream_event();
+DROP FUNCTION IF EXISTS add_thread_votes_stream_event();
+DROP FUNCTION IF EXISTS add_post_counters_stream_event();
+DROP FUNCTION IF EXISTS notify_stream_event();
+DROP FUNCTION IF EXISTS add_thread_stream_event();
+DROP FUNCTION IF EXISTS add_notification_stream_event();
 
 DROP TRIGGER IF EXISTS on_vote_insert ON votes;
 DROP TRIGGER IF EXISTS on_vote_update ON votes;
+DROP TRIGGER IF EXISTS on_vote_delete ON votes;
+DROP TRIGGER IF EXISTS on_post_vote ON post_votes;
+DROP TRIGGER IF EXISTS on_post_reaction ON post_reactions;
+DROP TRIGGER IF EXISTS on_vote_reputation ON votes;
+DROP TRIGGER IF EXISTS on_post_vote_reputation ON post_votes;
+DROP TRIGGER IF EXISTS on_post_activity ON posts;
+DROP TRIGGER IF EXISTS on_thread_activity ON threads;
+DROP TRIGGER IF EXISTS on_vote_daily_votes ON votes;
+DROP TRIGGER IF EXISTS on_thread_move_daily_votes ON threads;
 DROP TRIGGER IF EXISTS on_thread_insert ON threads;
 DROP TRIGGER IF EXISTS on_posts_insert ON posts;
+DROP TRIGGER IF EXISTS on_thread_search_update ON threads;
+DROP TRIGGER IF EXISTS on_post_search_update ON posts;
+DROP TRIGGER IF EXISTS on_post_stream_event ON posts;
+DROP TRIGGER IF EXISTS on_thread_votes_stream_event ON threads;
+DROP TRIGGER IF EXISTS on_post_counters_stream_event ON posts;
+DROP TRIGGER IF EXISTS on_stream_event_insert ON stream_events;
+DROP TRIGGER IF EXISTS on_thread_stream_event ON threads;
+DROP TRIGGER IF EXISTS on_notification_stream_event ON notifications;
 
 DROP INDEX IF EXISTS idx_users_email;
 DROP INDEX IF EXISTS idx_users_nickname;
+DROP INDEX IF EXISTS idx_users_nickname_trgm;
+DROP INDEX IF EXISTS idx_users_fullname_trgm;
 DROP INDEX IF EXISTS idx_forums_slug;
+DROP INDEX IF EXISTS idx_forums_title;
+DROP INDEX IF EXISTS idx_forums_last_activity;
+DROP INDEX IF EXISTS idx_forums_posts;
+DROP INDEX IF EXISTS idx_forums_parent;
 DROP INDEX IF EXISTS idx_threads_slug;
 DROP INDEX IF EXISTS idx_threads_forum;
 DROP INDEX IF EXISTS idx_threads_created;
 DROP INDEX IF EXISTS idx_threads_created_forum;
+DROP INDEX IF EXISTS idx_threads_pinned;
+DROP INDEX IF EXISTS idx_threads_announcement;
+DROP INDEX IF EXISTS idx_threads_forum_last_post;
+DROP INDEX IF EXISTS idx_threads_forum_votes;
+DROP INDEX IF EXISTS idx_threads_forum_posts;
+DROP INDEX IF EXISTS idx_threads_forum_hot;
+DROP INDEX IF EXISTS idx_threads_tags;
+DROP INDEX IF EXISTS idx_threads_search;
 DROP INDEX IF EXISTS idx_posts_path;
 DROP INDEX IF EXISTS idx_posts_thread;
 DROP INDEX IF EXISTS idx_posts_thread_id;
+DROP INDEX IF EXISTS idx_posts_forum_author;
+DROP INDEX IF EXISTS idx_posts_search;
 DROP INDEX IF EXISTS idx_votes_nickname_thread;
+DROP INDEX IF EXISTS idx_votes_thread;
+DROP INDEX IF EXISTS idx_mentions_user_post;
+DROP INDEX IF EXISTS idx_notifications_user;
+DROP INDEX IF EXISTS idx_notifications_user_unread;
+DROP INDEX IF EXISTS idx_thread_subscriptions_thread;
+DROP INDEX IF EXISTS idx_forum_subscriptions_forum;
+DROP INDEX IF EXISTS idx_stream_events_channel;
+DROP INDEX IF EXISTS idx_stream_events_created;
+DROP INDEX IF EXISTS idx_webhooks_forum;
+DROP INDEX IF EXISTS idx_webhook_deliveries_webhook;
+DROP INDEX IF EXISTS idx_webhook_deliveries_pending;
+DROP INDEX IF EXISTS idx_outbox_pending;
+DROP INDEX IF EXISTS idx_outbox_published;
+DROP INDEX IF EXISTS idx_post_reactions_post;
+DROP INDEX IF EXISTS idx_forum_user_stats_posts;
+DROP INDEX IF EXISTS idx_user_stats_posts;
+DROP INDEX IF EXISTS idx_user_stats_threads;
+DROP INDEX IF EXISTS idx_trending_threads_score;
+DROP INDEX IF EXISTS idx_posts_created;
+DROP INDEX IF EXISTS idx_votes_created;
 DROP INDEX IF EXISTS idx_forum_users_user_id;
 DROP INDEX IF EXISTS idx_forum_users_forum_id;
 DROP INDEX IF EXISTS idx_forum_users_user_id_forum_id;
@@ -36,7 +133,13 @@ CREATE UNLOGGED TABLE IF NOT EXISTS forums(
     username CITEXT NOT NULL,
     slug CITEXT NOT NULL UNIQUE,
     posts BIGINT DEFAULT 0,
-    threads INT DEFAULT 0
+    threads INT DEFAULT 0,
+    description TEXT NOT NULL DEFAULT '',
+    last_activity TIMESTAMPTZ NOT NULL DEFAULT now(),
+    archived BOOL NOT NULL DEFAULT false,
+    parent CITEXT NOT NULL DEFAULT '',
+    position INT NOT NULL DEFAULT 0,
+    is_category BOOL NOT NULL DEFAULT false
 );
 
 CREATE UNLOGGED TABLE IF NOT EXISTS posts(
@@ -48,7 +151,10 @@ CREATE UNLOGGED TABLE IF NOT EXISTS posts(
     isEdited BOOL DEFAULT false,
     forum CITEXT,
     thread INT,
-    created TIMESTAMPTZ DEFAULT now()
+    created TIMESTAMPTZ DEFAULT now(),
+    search_vector TSVECTOR,
+    votes INT NOT NULL DEFAULT 0,
+    reactions JSONB NOT NULL DEFAULT '{}'
 );
 
 CREATE UNLOGGED TABLE IF NOT EXISTS threads(
@@ -59,7 +165,17 @@ CREATE UNLOGGED TABLE IF NOT EXISTS threads(
     message TEXT NOT NULL,
     votes INT DEFAULT 0,
     slug CITEXT,
-    created TIMESTAMPTZ DEFAULT now()
+    created TIMESTAMPTZ DEFAULT now(),
+    pinned BOOL NOT NULL DEFAULT false,
+    pin_order INT NOT NULL DEFAULT 0,
+    announcement BOOL NOT NULL DEFAULT false,
+    posts INT NOT NULL DEFAULT 0,
+    last_post_id BIGINT NOT NULL DEFAULT 0,
+    last_post_author CITEXT NOT NULL DEFAULT '',
+    last_post_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    moved_to BIGINT NOT NULL DEFAULT 0,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    search_vector TSVECTOR
 );
 
 CREATE UNLOGGED TABLE IF NOT EXISTS users(
@@ -67,7 +183,9 @@ CREATE UNLOGGED TABLE IF NOT EXISTS users(
     nickname CITEXT NOT NULL UNIQUE,
     fullname CITEXT NOT NULL,
     about TEXT,
-    email CITEXT NOT NULL UNIQUE
+    email CITEXT NOT NULL UNIQUE,
+    is_admin BOOL NOT NULL DEFAULT false,
+    created TIMESTAMPTZ NOT NULL DEFAULT now()
 );
 
 CREATE UNLOGGED TABLE IF NOT EXISTS forum_users(
@@ -76,11 +194,207 @@ CREATE UNLOGGED TABLE IF NOT EXISTS forum_users(
     forum_id BIGINT REFERENCES forums(id) NOT NULL
 );
 
+CREATE UNLOGGED TABLE IF NOT EXISTS forum_settings(
+    forum_id BIGINT REFERENCES forums(id) NOT NULL PRIMARY KEY,
+    rules TEXT NOT NULL DEFAULT '',
+    read_only BOOL NOT NULL DEFAULT false,
+    anonymous_read BOOL NOT NULL DEFAULT true,
+    min_account_age INT NOT NULL DEFAULT 0,
+    max_post_length INT NOT NULL DEFAULT 0,
+    allowed_tags TEXT[] NOT NULL DEFAULT '{}',
+    anonymous_votes BOOL NOT NULL DEFAULT false,
+    min_reputation INT NOT NULL DEFAULT 0
+);
+
 CREATE UNLOGGED TABLE IF NOT EXISTS votes(
     id BIGSERIAL NOT NULL PRIMARY KEY,
     user_id BIGINT REFERENCES users(id) NOT NULL,
     thread_id BIGINT REFERENCES threads(id) NOT NULL,
-    voice INT NOT NULL
+    voice INT NOT NULL CHECK (voice IN (-1, 1)),
+    created TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS post_votes(
+    user_id BIGINT REFERENCES users(id) NOT NULL,
+    post_id BIGINT REFERENCES posts(id) ON DELETE CASCADE NOT NULL,
+    voice INT NOT NULL CHECK (voice IN (-1, 1)),
+    created TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS post_reactions(
+    user_id BIGINT REFERENCES users(id) NOT NULL,
+    post_id BIGINT REFERENCES posts(id) ON DELETE CASCADE NOT NULL,
+    emoji TEXT NOT NULL,
+    created TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, post_id, emoji)
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS user_reputation(
+    user_id BIGINT REFERENCES users(id) NOT NULL,
+    forum_id BIGINT REFERENCES forums(id) ON DELETE CASCADE NOT NULL,
+    score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, forum_id)
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS forum_daily_stats(
+    forum_id BIGINT REFERENCES forums(id) ON DELETE CASCADE NOT NULL,
+    day DATE NOT NULL,
+    posts INT NOT NULL DEFAULT 0,
+    threads INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (forum_id, day)
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS forum_user_daily_stats(
+    forum_id BIGINT REFERENCES forums(id) ON DELETE CASCADE NOT NULL,
+    day DATE NOT NULL,
+    user_id BIGINT REFERENCES users(id) NOT NULL,
+    posts INT NOT NULL DEFAULT 0,
+    threads INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (forum_id, day, user_id)
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS forum_user_stats(
+    forum_id BIGINT REFERENCES forums(id) ON DELETE CASCADE NOT NULL,
+    user_id BIGINT REFERENCES users(id) NOT NULL,
+    posts INT NOT NULL DEFAULT 0,
+    threads INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (forum_id, user_id)
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS user_stats(
+    user_id BIGINT REFERENCES users(id) NOT NULL PRIMARY KEY,
+    posts INT NOT NULL DEFAULT 0,
+    threads INT NOT NULL DEFAULT 0
+);
+
+-- Net votes cast on the threads of a forum per day, for the top threads of
+-- the stats window.
+CREATE UNLOGGED TABLE IF NOT EXISTS thread_daily_votes(
+    forum_id BIGINT REFERENCES forums(id) ON DELETE CASCADE NOT NULL,
+    day DATE NOT NULL,
+    thread_id BIGINT REFERENCES threads(id) ON DELETE CASCADE NOT NULL,
+    votes INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (forum_id, day, thread_id)
+);
+
+-- Rankings of the trending job, one per window.
+CREATE UNLOGGED TABLE IF NOT EXISTS trending_threads(
+    period TEXT NOT NULL,
+    thread_id BIGINT REFERENCES threads(id) ON DELETE CASCADE NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    recent_posts INT NOT NULL DEFAULT 0,
+    recent_votes INT NOT NULL DEFAULT 0,
+    computed TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (period, thread_id)
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS mentions(
+    post_id BIGINT REFERENCES posts(id) ON DELETE CASCADE NOT NULL,
+    user_id BIGINT REFERENCES users(id) NOT NULL,
+    created TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (post_id, user_id)
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS notifications(
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    user_id BIGINT REFERENCES users(id) NOT NULL,
+    type TEXT NOT NULL,
+    actor CITEXT NOT NULL,
+    thread BIGINT NOT NULL DEFAULT 0,
+    post BIGINT NOT NULL DEFAULT 0,
+    is_read BOOL NOT NULL DEFAULT false,
+    created TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS notification_preferences(
+    user_id BIGINT REFERENCES users(id) NOT NULL PRIMARY KEY,
+    reply BOOL NOT NULL DEFAULT true,
+    thread_post BOOL NOT NULL DEFAULT true,
+    vote BOOL NOT NULL DEFAULT true,
+    mention BOOL NOT NULL DEFAULT true
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS thread_subscriptions(
+    user_id BIGINT REFERENCES users(id) NOT NULL,
+    thread_id BIGINT REFERENCES threads(id) ON DELETE CASCADE NOT NULL,
+    created TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, thread_id)
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS forum_subscriptions(
+    user_id BIGINT REFERENCES users(id) NOT NULL,
+    forum_id BIGINT REFERENCES forums(id) ON DELETE CASCADE NOT NULL,
+    created TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, forum_id)
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS feed_cursors(
+    user_id BIGINT REFERENCES users(id) NOT NULL PRIMARY KEY,
+    last_post_id BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS thread_reads(
+    user_id BIGINT REFERENCES users(id) NOT NULL,
+    thread_id BIGINT REFERENCES threads(id) ON DELETE CASCADE NOT NULL,
+    last_post_id BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, thread_id)
+);
+
+-- Posts read past the marker of thread_reads, out of the order of ids as in
+-- the tree sorts. They are dropped once the marker reaches them.
+CREATE UNLOGGED TABLE IF NOT EXISTS thread_read_posts(
+    user_id BIGINT REFERENCES users(id) NOT NULL,
+    thread_id BIGINT REFERENCES threads(id) ON DELETE CASCADE NOT NULL,
+    post_id BIGINT NOT NULL,
+    PRIMARY KEY (user_id, thread_id, post_id)
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS stream_events(
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    channel TEXT NOT NULL,
+    type TEXT NOT NULL,
+    payload JSON NOT NULL,
+    created TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS webhooks(
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    forum_id BIGINT NOT NULL REFERENCES forums (id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    events TEXT[] NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS webhook_deliveries(
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    webhook_id BIGINT NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    payload JSON NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt TIMESTAMPTZ NOT NULL DEFAULT now(),
+    status_code INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMPTZ
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS outbox(
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    aggregate TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    type TEXT NOT NULL,
+    payload JSON NOT NULL,
+    created TIMESTAMPTZ NOT NULL DEFAULT now(),
+    attempts INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMPTZ NOT NULL DEFAULT now(),
+    published_to TEXT[] NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL DEFAULT '',
+    published_at TIMESTAMPTZ
 );
 
 CREATE FUNCTION update_thread_votes_after_insert()
@@ -107,11 +421,7 @@ CREATE FUNCTION update_thread_votes_after_update()
         END IF;
         UPDATE threads
         SET
-            votes = votes + CASE
-                WHEN NEW.voice = -1
-                THEN -2
-                ELSE 2
-                END
+            votes = votes + NEW.voice - OLD.voice
         WHERE id = NEW.thread_id;
         RETURN NULL;
     END;
@@ -121,6 +431,256 @@ CREATE TRIGGER on_vote_update
     AFTER UPDATE ON votes
     FOR EACH ROW EXECUTE PROCEDURE update_thread_votes_after_update();
 
+CREATE FUNCTION update_thread_votes_after_delete()
+    RETURNS TRIGGER AS '
+    BEGIN
+        UPDATE threads
+        SET
+            votes = votes - OLD.voice
+        WHERE id = OLD.thread_id;
+        RETURN NULL;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_vote_delete
+    AFTER DELETE ON votes
+    FOR EACH ROW EXECUTE PROCEDURE update_thread_votes_after_delete();
+
+CREATE FUNCTION update_post_votes()
+    RETURNS TRIGGER AS '
+    BEGIN
+        IF TG_OP = ''DELETE''
+        THEN
+            UPDATE posts SET votes = votes - OLD.voice WHERE id = OLD.post_id;
+        ELSIF TG_OP = ''UPDATE''
+        THEN
+            UPDATE posts SET votes = votes + NEW.voice - OLD.voice WHERE id = NEW.post_id;
+        ELSE
+            UPDATE posts SET votes = votes + NEW.voice WHERE id = NEW.post_id;
+        END IF;
+        RETURN NULL;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_post_vote
+    AFTER INSERT OR UPDATE OR DELETE ON post_votes
+    FOR EACH ROW EXECUTE PROCEDURE update_post_votes();
+
+-- posts.reactions keeps the count of every emoji, emojis nobody uses any more
+-- are removed.
+CREATE FUNCTION update_post_reactions()
+    RETURNS TRIGGER AS '
+    BEGIN
+        IF TG_OP = ''DELETE''
+        THEN
+            UPDATE posts
+            SET reactions = CASE
+                WHEN (reactions->>OLD.emoji)::int > 1
+                THEN jsonb_set(reactions, ARRAY[OLD.emoji], to_jsonb((reactions->>OLD.emoji)::int - 1))
+                ELSE reactions - OLD.emoji
+                END
+            WHERE id = OLD.post_id;
+        ELSE
+            UPDATE posts
+            SET reactions = jsonb_set(reactions, ARRAY[NEW.emoji], to_jsonb(COALESCE((reactions->>NEW.emoji)::int, 0) + 1))
+            WHERE id = NEW.post_id;
+        END IF;
+        RETURN NULL;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_post_reaction
+    AFTER INSERT OR DELETE ON post_reactions
+    FOR EACH ROW EXECUTE PROCEDURE update_post_reactions();
+
+-- Reputation halves every 90 days. The score is stored as it was at its last
+-- update, so a vote only decays and adds to the row of the author and forum.
+CREATE FUNCTION decayed_reputation(score DOUBLE PRECISION, updated TIMESTAMPTZ)
+    RETURNS DOUBLE PRECISION AS '
+    SELECT score * power(0.5, extract(epoch FROM now() - updated) / (90 * 86400));
+' LANGUAGE sql STABLE;
+
+-- Votes of authors on their own threads and posts don't count.
+CREATE FUNCTION add_reputation(author_nickname CITEXT, forum_slug CITEXT, voter_id BIGINT, delta INT)
+    RETURNS VOID AS '
+    INSERT INTO user_reputation (user_id, forum_id, score)
+    SELECT u.id, f.id, delta FROM users u, forums f
+    WHERE u.nickname = author_nickname AND f.slug = forum_slug AND u.id <> voter_id
+    ON CONFLICT (user_id, forum_id) DO UPDATE
+    SET score = decayed_reputation(user_reputation.score, user_reputation.updated) + EXCLUDED.score, updated = now();
+' LANGUAGE sql;
+
+CREATE FUNCTION update_thread_author_reputation()
+    RETURNS TRIGGER AS '
+    BEGIN
+        IF TG_OP = ''DELETE''
+        THEN
+            PERFORM add_reputation(t.author, t.forum, OLD.user_id, -OLD.voice) FROM threads t WHERE t.id = OLD.thread_id;
+        ELSIF TG_OP = ''UPDATE''
+        THEN
+            PERFORM add_reputation(t.author, t.forum, NEW.user_id, NEW.voice - OLD.voice) FROM threads t WHERE t.id = NEW.thread_id;
+        ELSE
+            PERFORM add_reputation(t.author, t.forum, NEW.user_id, NEW.voice) FROM threads t WHERE t.id = NEW.thread_id;
+        END IF;
+        RETURN NULL;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_vote_reputation
+    AFTER INSERT OR UPDATE OR DELETE ON votes
+    FOR EACH ROW EXECUTE PROCEDURE update_thread_author_reputation();
+
+CREATE FUNCTION update_post_author_reputation()
+    RETURNS TRIGGER AS '
+    BEGIN
+        IF TG_OP = ''DELETE''
+        THEN
+            PERFORM add_reputation(p.author, p.forum, OLD.user_id, -OLD.voice) FROM posts p WHERE p.id = OLD.post_id;
+        ELSIF TG_OP = ''UPDATE''
+        THEN
+            PERFORM add_reputation(p.author, p.forum, NEW.user_id, NEW.voice - OLD.voice) FROM posts p WHERE p.id = NEW.post_id;
+        ELSE
+            PERFORM add_reputation(p.author, p.forum, NEW.user_id, NEW.voice) FROM posts p WHERE p.id = NEW.post_id;
+        END IF;
+        RETURN NULL;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_post_vote_reputation
+    AFTER INSERT OR UPDATE OR DELETE ON post_votes
+    FOR EACH ROW EXECUTE PROCEDURE update_post_author_reputation();
+
+-- The stats rollups are kept up to date by the triggers of threads and posts,
+-- so the stats never scan them. Days are counted in UTC.
+CREATE FUNCTION add_forum_activity(forum_slug CITEXT, author_nickname CITEXT, activity_day DATE, post_delta INT, thread_delta INT)
+    RETURNS VOID AS '
+    DECLARE
+        activity_forum_id BIGINT;
+        activity_user_id BIGINT;
+    BEGIN
+        SELECT id INTO activity_forum_id FROM forums WHERE slug = forum_slug;
+        SELECT id INTO activity_user_id FROM users WHERE nickname = author_nickname;
+        IF activity_forum_id IS NULL OR activity_user_id IS NULL
+        THEN
+            RETURN;
+        END IF;
+
+        INSERT INTO forum_daily_stats AS s (forum_id, day, posts, threads)
+        VALUES (activity_forum_id, activity_day, post_delta, thread_delta)
+        ON CONFLICT (forum_id, day) DO UPDATE
+        SET posts = s.posts + EXCLUDED.posts, threads = s.threads + EXCLUDED.threads;
+
+        INSERT INTO forum_user_daily_stats AS s (forum_id, day, user_id, posts, threads)
+        VALUES (activity_forum_id, activity_day, activity_user_id, post_delta, thread_delta)
+        ON CONFLICT (forum_id, day, user_id) DO UPDATE
+        SET posts = s.posts + EXCLUDED.posts, threads = s.threads + EXCLUDED.threads;
+
+        INSERT INTO forum_user_stats AS s (forum_id, user_id, posts, threads)
+        VALUES (activity_forum_id, activity_user_id, post_delta, thread_delta)
+        ON CONFLICT (forum_id, user_id) DO UPDATE
+        SET posts = s.posts + EXCLUDED.posts, threads = s.threads + EXCLUDED.threads;
+
+        INSERT INTO user_stats AS s (user_id, posts, threads)
+        VALUES (activity_user_id, post_delta, thread_delta)
+        ON CONFLICT (user_id) DO UPDATE
+        SET posts = s.posts + EXCLUDED.posts, threads = s.threads + EXCLUDED.threads;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE FUNCTION update_post_activity()
+    RETURNS TRIGGER AS '
+    BEGIN
+        IF TG_OP = ''UPDATE'' AND OLD.forum IS NOT DISTINCT FROM NEW.forum
+        THEN
+            RETURN NULL;
+        END IF;
+        IF TG_OP IN (''UPDATE'', ''DELETE'')
+        THEN
+            PERFORM add_forum_activity(OLD.forum, OLD.author, (COALESCE(OLD.created, now()) AT TIME ZONE ''UTC'')::date, -1, 0);
+        END IF;
+        IF TG_OP IN (''UPDATE'', ''INSERT'')
+        THEN
+            PERFORM add_forum_activity(NEW.forum, NEW.author, (COALESCE(NEW.created, now()) AT TIME ZONE ''UTC'')::date, 1, 0);
+        END IF;
+        RETURN NULL;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_post_activity
+    AFTER INSERT OR UPDATE OF forum OR DELETE ON posts
+    FOR EACH ROW EXECUTE PROCEDURE update_post_activity();
+
+-- Redirect stubs left behind by thread moves are not counted as threads.
+CREATE FUNCTION update_thread_activity()
+    RETURNS TRIGGER AS '
+    BEGIN
+        IF TG_OP = ''UPDATE'' AND OLD.forum IS NOT DISTINCT FROM NEW.forum
+        THEN
+            RETURN NULL;
+        END IF;
+        IF TG_OP IN (''UPDATE'', ''DELETE'') AND OLD.moved_to = 0
+        THEN
+            PERFORM add_forum_activity(OLD.forum, OLD.author, (COALESCE(OLD.created, now()) AT TIME ZONE ''UTC'')::date, 0, -1);
+        END IF;
+        IF TG_OP IN (''UPDATE'', ''INSERT'') AND NEW.moved_to = 0
+        THEN
+            PERFORM add_forum_activity(NEW.forum, NEW.author, (COALESCE(NEW.created, now()) AT TIME ZONE ''UTC'')::date, 0, 1);
+        END IF;
+        RETURN NULL;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_thread_activity
+    AFTER INSERT OR UPDATE OF forum OR DELETE ON threads
+    FOR EACH ROW EXECUTE PROCEDURE update_thread_activity();
+
+-- A vote counts on the day it was cast, a changed vote moves to the day of
+-- the change as its created time does.
+CREATE FUNCTION add_thread_votes(vote_thread_id BIGINT, vote_day DATE, delta INT)
+    RETURNS VOID AS '
+    INSERT INTO thread_daily_votes AS s (forum_id, day, thread_id, votes)
+    SELECT f.id, vote_day, t.id, delta FROM threads t JOIN forums f ON f.slug = t.forum
+    WHERE t.id = vote_thread_id
+    ON CONFLICT (forum_id, day, thread_id) DO UPDATE
+    SET votes = s.votes + EXCLUDED.votes;
+' LANGUAGE sql;
+
+CREATE FUNCTION update_thread_daily_votes()
+    RETURNS TRIGGER AS '
+    BEGIN
+        IF TG_OP IN (''UPDATE'', ''DELETE'')
+        THEN
+            PERFORM add_thread_votes(OLD.thread_id, (OLD.created AT TIME ZONE ''UTC'')::date, -OLD.voice);
+        END IF;
+        IF TG_OP IN (''UPDATE'', ''INSERT'')
+        THEN
+            PERFORM add_thread_votes(NEW.thread_id, (NEW.created AT TIME ZONE ''UTC'')::date, NEW.voice);
+        END IF;
+        RETURN NULL;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_vote_daily_votes
+    AFTER INSERT OR UPDATE OR DELETE ON votes
+    FOR EACH ROW EXECUTE PROCEDURE update_thread_daily_votes();
+
+-- The votes of a moved thread go with it to its new forum.
+CREATE FUNCTION move_thread_daily_votes()
+    RETURNS TRIGGER AS '
+    BEGIN
+        IF OLD.forum IS DISTINCT FROM NEW.forum
+        THEN
+            UPDATE thread_daily_votes SET forum_id = (SELECT id FROM forums WHERE slug = NEW.forum)
+            WHERE thread_id = NEW.id;
+        END IF;
+        RETURN NULL;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_thread_move_daily_votes
+    AFTER UPDATE OF forum ON threads
+    FOR EACH ROW EXECUTE PROCEDURE move_thread_daily_votes();
+
 CREATE FUNCTION insert_forum_users()
     RETURNS TRIGGER AS '
     BEGIN
@@ -129,29 +689,198 @@ CREATE FUNCTION insert_forum_users()
     END;
 ' LANGUAGE plpgsql;
 
+-- Redirect stubs of moved threads don't make their authors forum users.
 CREATE TRIGGER on_thread_insert
     AFTER INSERT ON threads
-    FOR EACH ROW EXECUTE PROCEDURE insert_forum_users();
+    FOR EACH ROW WHEN (NEW.moved_to = 0) EXECUTE PROCEDURE insert_forum_users();
 
 CREATE TRIGGER on_posts_insert
     AFTER INSERT ON posts
     FOR EACH ROW EXECUTE PROCEDURE insert_forum_users();
 
+-- Titles weigh more than messages in search ranking.
+CREATE FUNCTION update_thread_search_vector()
+    RETURNS TRIGGER AS '
+    BEGIN
+        NEW.search_vector :=
+            setweight(to_tsvector(''english'', coalesce(NEW.title, '''')), ''A'') ||
+            setweight(to_tsvector(''english'', coalesce(NEW.message, '''')), ''B'');
+        RETURN NEW;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_thread_search_update
+    BEFORE INSERT OR UPDATE OF title, message ON threads
+    FOR EACH ROW EXECUTE PROCEDURE update_thread_search_vector();
+
+CREATE TRIGGER on_post_search_update
+    BEFORE INSERT OR UPDATE OF message ON posts
+    FOR EACH ROW EXECUTE PROCEDURE tsvector_update_trigger(search_vector, 'pg_catalog.english', message);
+
+-- Changes of posts and thread votes are stored as stream events of the thread
+-- channel; the notification only tells the listeners to read the new events.
+CREATE FUNCTION add_post_stream_event()
+    RETURNS TRIGGER AS '
+    BEGIN
+        IF TG_OP = ''DELETE''
+        THEN
+            INSERT INTO stream_events (channel, type, payload)
+            VALUES (''thread:'' || OLD.thread, ''post_deleted'', json_build_object(''id'', OLD.id, ''thread'', OLD.thread));
+            RETURN NULL;
+        END IF;
+        INSERT INTO stream_events (channel, type, payload)
+        VALUES (''thread:'' || NEW.thread, CASE TG_OP WHEN ''INSERT'' THEN ''post_created'' ELSE ''post_updated'' END,
+            json_build_object(''id'', NEW.id, ''parent'', NEW.parent, ''author'', NEW.author, ''message'', NEW.message,
+                ''isEdited'', NEW.isEdited, ''forum'', NEW.forum, ''thread'', NEW.thread, ''created'', NEW.created));
+        RETURN NULL;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_post_stream_event
+    AFTER INSERT OR UPDATE OF message OR DELETE ON posts
+    FOR EACH ROW EXECUTE PROCEDURE add_post_stream_event();
+
+CREATE FUNCTION add_thread_votes_stream_event()
+    RETURNS TRIGGER AS '
+    BEGIN
+        INSERT INTO stream_events (channel, type, payload)
+        VALUES (''thread:'' || NEW.id, ''votes'', json_build_object(''thread'', NEW.id, ''votes'', NEW.votes));
+        RETURN NULL;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_thread_votes_stream_event
+    AFTER UPDATE OF votes ON threads
+    FOR EACH ROW WHEN (OLD.votes IS DISTINCT FROM NEW.votes)
+    EXECUTE PROCEDURE add_thread_votes_stream_event();
+
+CREATE FUNCTION add_post_counters_stream_event()
+    RETURNS TRIGGER AS '
+    BEGIN
+        INSERT INTO stream_events (channel, type, payload)
+        VALUES (''thread:'' || NEW.thread, ''post_counters'',
+            json_build_object(''id'', NEW.id, ''thread'', NEW.thread, ''votes'', NEW.votes, ''reactions'', NEW.reactions));
+        RETURN NULL;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_post_counters_stream_event
+    AFTER UPDATE OF votes, reactions ON posts
+    FOR EACH ROW WHEN (OLD.votes IS DISTINCT FROM NEW.votes OR OLD.reactions IS DISTINCT FROM NEW.reactions)
+    EXECUTE PROCEDURE add_post_counters_stream_event();
+
+CREATE FUNCTION add_thread_stream_event()
+    RETURNS TRIGGER AS '
+    BEGIN
+        INSERT INTO stream_events (channel, type, payload)
+        VALUES (''forum:'' || NEW.forum, ''thread_created'',
+            json_build_object(''id'', NEW.id, ''title'', NEW.title, ''author'', NEW.author, ''forum'', NEW.forum,
+                ''message'', NEW.message, ''slug'', NEW.slug, ''created'', NEW.created));
+        RETURN NULL;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_thread_stream_event
+    AFTER INSERT ON threads
+    FOR EACH ROW WHEN (NEW.moved_to = 0)
+    EXECUTE PROCEDURE add_thread_stream_event();
+
+CREATE FUNCTION add_notification_stream_event()
+    RETURNS TRIGGER AS '
+    BEGIN
+        INSERT INTO stream_events (channel, type, payload)
+        VALUES (''user:'' || NEW.user_id, ''notification'',
+            json_build_object(''id'', NEW.id, ''type'', NEW.type, ''actor'', NEW.actor, ''thread'', NEW.thread,
+                ''post'', NEW.post, ''read'', NEW.is_read, ''created'', NEW.created));
+        RETURN NULL;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_notification_stream_event
+    AFTER INSERT ON notifications
+    FOR EACH ROW EXECUTE PROCEDURE add_notification_stream_event();
+
+CREATE FUNCTION notify_stream_event()
+    RETURNS TRIGGER AS '
+    BEGIN
+        PERFORM pg_notify(''stream_events'', NEW.id::text);
+        RETURN NULL;
+    END;
+' LANGUAGE plpgsql;
+
+CREATE TRIGGER on_stream_event_insert
+    AFTER INSERT ON stream_events
+    FOR EACH ROW EXECUTE PROCEDURE notify_stream_event();
+
+-- Reddit-style hot score: log-scaled engagement plus a creation time bonus, so
+-- newer threads outrank older ones with the same activity. It depends on the
+-- row only, which keeps it indexable and stable across paginated requests.
+CREATE FUNCTION thread_hot(votes INT, posts INT, created TIMESTAMPTZ)
+    RETURNS DOUBLE PRECISION AS '
+    SELECT sign((votes + posts)::float8) * log(greatest(abs(votes + posts), 1)::float8)
+        + extract(epoch FROM created)::float8 / 45000;
+' LANGUAGE sql IMMUTABLE;
+
 CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
 CREATE UNIQUE INDEX IF NOT EXISTS idx_users_nickname ON users (nickname);
+CREATE INDEX IF NOT EXISTS idx_users_nickname_trgm ON users USING GIN ((nickname::text) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_fullname_trgm ON users USING GIN ((fullname::text) gin_trgm_ops);
 
 CREATE UNIQUE INDEX IF NOT EXISTS idx_forums_slug ON forums (slug);
+CREATE INDEX IF NOT EXISTS idx_forums_title ON forums (title, id) WHERE NOT archived;
+CREATE INDEX IF NOT EXISTS idx_forums_last_activity ON forums (last_activity, id) WHERE NOT archived;
+CREATE INDEX IF NOT EXISTS idx_forums_posts ON forums (posts, id) WHERE NOT archived;
+CREATE INDEX IF NOT EXISTS idx_forums_parent ON forums (parent);
 
 CREATE INDEX IF NOT EXISTS idx_threads_slug ON threads (slug);
 CREATE INDEX IF NOT EXISTS idx_threads_forum ON threads (forum);
 CREATE INDEX IF NOT EXISTS idx_threads_created ON threads (created);
 CREATE INDEX IF NOT EXISTS idx_threads_created_forum ON threads (forum, created);
+CREATE INDEX IF NOT EXISTS idx_threads_pinned ON threads (forum, pin_order) WHERE pinned;
+CREATE INDEX IF NOT EXISTS idx_threads_announcement ON threads (pin_order) WHERE announcement;
+CREATE INDEX IF NOT EXISTS idx_threads_forum_last_post ON threads (forum, last_post_at, id);
+CREATE INDEX IF NOT EXISTS idx_threads_forum_votes ON threads (forum, votes, id);
+CREATE INDEX IF NOT EXISTS idx_threads_forum_posts ON threads (forum, posts, id);
+CREATE INDEX IF NOT EXISTS idx_threads_forum_hot ON threads (forum, thread_hot(votes, posts, created), id);
+CREATE INDEX IF NOT EXISTS idx_threads_tags ON threads USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_threads_search ON threads USING GIN (search_vector);
 
 CREATE INDEX IF NOT EXISTS idx_posts_path ON posts USING GIN (path);
 CREATE INDEX IF NOT EXISTS idx_posts_thread ON posts (thread);
 CREATE INDEX IF NOT EXISTS idx_posts_thread_id ON posts (thread, id);
+CREATE INDEX IF NOT EXISTS idx_posts_forum_author ON posts (forum, author);
+CREATE INDEX IF NOT EXISTS idx_posts_search ON posts USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_posts_created ON posts (created);
 
 CREATE UNIQUE INDEX IF NOT EXISTS idx_votes_nickname_thread ON votes (user_id, thread_id);
+CREATE INDEX IF NOT EXISTS idx_votes_thread ON votes (thread_id, id);
+CREATE INDEX IF NOT EXISTS idx_votes_created ON votes (created);
+
+CREATE INDEX IF NOT EXISTS idx_forum_user_stats_posts ON forum_user_stats (forum_id, posts DESC);
+CREATE INDEX IF NOT EXISTS idx_user_stats_posts ON user_stats (posts DESC);
+CREATE INDEX IF NOT EXISTS idx_user_stats_threads ON user_stats (threads DESC);
+
+CREATE INDEX IF NOT EXISTS idx_trending_threads_score ON trending_threads (period, score DESC);
+
+CREATE INDEX IF NOT EXISTS idx_post_reactions_post ON post_reactions (post_id, emoji);
+
+CREATE INDEX IF NOT EXISTS idx_mentions_user_post ON mentions (user_id, post_id);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, id);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_unread ON notifications (user_id, id) WHERE NOT is_read;
+
+CREATE INDEX IF NOT EXISTS idx_thread_subscriptions_thread ON thread_subscriptions (thread_id);
+CREATE INDEX IF NOT EXISTS idx_forum_subscriptions_forum ON forum_subscriptions (forum_id);
+
+CREATE INDEX IF NOT EXISTS idx_stream_events_channel ON stream_events (channel, id);
+CREATE INDEX IF NOT EXISTS idx_stream_events_created ON stream_events (created);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_forum ON webhooks (forum_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending ON webhook_deliveries (next_attempt) WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox (id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_published ON outbox (published_at);
 
 CREATE INDEX idx_forum_users_user_id ON forum_users(user_id);
 CREATE INDEX idx_forum_users_forum_id ON forum_users(forum_id);
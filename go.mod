@@ -4,6 +4,7 @@ go 1.17
 
 require (
 	github.com/gorilla/mux v1.8.0
+	github.com/gorilla/websocket v1.5.0
 	github.com/prometheus/client_golang v1.12.1
 	github.com/spf13/viper v1.10.1
 )
@@ -3,8 +3,10 @@ package delivery
 import (
 	"errors"
 	"forumApp/internal/forumapp/models"
+	"forumApp/internal/pkg/authutils"
 	"forumApp/internal/pkg/ioutils"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 )
@@ -24,7 +26,7 @@ func (uh *ForumHandler) CreateForumHandler(w http.ResponseWriter, r *http.Reques
 	}
 
 	forum, code, err := uh.ForumUsecase.CreateForum(newForum)
-	if code == http.StatusNotFound {
+	if err != nil {
 		ioutils.SendError(w, code, err.Error())
 		return
 	}
@@ -36,7 +38,7 @@ func (uh *ForumHandler) ForumDetailsHandler(w http.ResponseWriter, r *http.Reque
 
 	slug := mux.Vars(r)["slug"]
 
-	findedForum, code, err := uh.ForumUsecase.GetForum(slug)
+	findedForum, code, err := uh.ForumUsecase.GetForum(slug, authutils.GetNickname(r))
 	if err != nil {
 		ioutils.SendError(w, code, err.Error())
 		return
@@ -45,6 +47,233 @@ func (uh *ForumHandler) ForumDetailsHandler(w http.ResponseWriter, r *http.Reque
 	ioutils.Send(w, code, findedForum)
 }
 
+func (uh *ForumHandler) GetForumsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	findedForums, code, err := uh.ForumUsecase.GetForums(r.URL.Query())
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, findedForums)
+}
+
+func (uh *ForumHandler) GetForumTreeHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	forumTree, code, err := uh.ForumUsecase.GetForumTree()
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, forumTree)
+}
+
+func (uh *ForumHandler) UpdateForumHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slug := mux.Vars(r)["slug"]
+
+	var newForum models.Forum
+	err := ioutils.ReadJSON(r, &newForum)
+	if err != nil {
+		ioutils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updatedForum, code, err := uh.ForumUsecase.UpdateForum(slug, authutils.GetNickname(r), newForum)
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, updatedForum)
+}
+
+func (uh *ForumHandler) DeleteForumHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slug := mux.Vars(r)["slug"]
+
+	code, err := uh.ForumUsecase.DeleteForum(slug, authutils.GetNickname(r))
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.SendWithoutBody(w, code)
+}
+
+func (uh *ForumHandler) SubscribeForumHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slug := mux.Vars(r)["slug"]
+
+	code, err := uh.ForumUsecase.SubscribeForum(slug, authutils.GetNickname(r), r.Method != http.MethodDelete)
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.SendWithoutBody(w, code)
+}
+
+func (uh *ForumHandler) GetForumStatsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slug := mux.Vars(r)["slug"]
+
+	forumStats, code, err := uh.ForumUsecase.GetForumStats(slug, authutils.GetNickname(r), r.URL.Query())
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, forumStats)
+}
+
+func (uh *ForumHandler) GetLeaderboardHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	leaderboard, code, err := uh.ForumUsecase.GetLeaderboard(r.URL.Query())
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, leaderboard)
+}
+
+func (uh *ForumHandler) GetTrendingHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	trendingThreads, code, err := uh.ForumUsecase.GetTrending(authutils.GetNickname(r), r.URL.Query())
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, trendingThreads)
+}
+
+func (uh *ForumHandler) CreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slug := mux.Vars(r)["slug"]
+
+	var newWebhook models.Webhook
+	err := ioutils.ReadJSON(r, &newWebhook)
+	if err != nil {
+		ioutils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	createdWebhook, code, err := uh.ForumUsecase.CreateWebhook(slug, authutils.GetNickname(r), newWebhook)
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, createdWebhook)
+}
+
+func (uh *ForumHandler) GetWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slug := mux.Vars(r)["slug"]
+
+	findedWebhooks, code, err := uh.ForumUsecase.GetWebhooks(slug, authutils.GetNickname(r))
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, findedWebhooks)
+}
+
+func (uh *ForumHandler) DeleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slug := mux.Vars(r)["slug"]
+	id := mux.Vars(r)["id"]
+
+	code, err := uh.ForumUsecase.DeleteWebhook(slug, id, authutils.GetNickname(r))
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.SendWithoutBody(w, code)
+}
+
+func (uh *ForumHandler) GetWebhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slug := mux.Vars(r)["slug"]
+	id := mux.Vars(r)["id"]
+
+	findedDeliveries, code, err := uh.ForumUsecase.GetWebhookDeliveries(slug, id, authutils.GetNickname(r), r.URL.Query())
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, findedDeliveries)
+}
+
+func (uh *ForumHandler) RedeliverWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slug := mux.Vars(r)["slug"]
+	id := mux.Vars(r)["id"]
+	deliveryId := mux.Vars(r)["delivery_id"]
+
+	createdDelivery, code, err := uh.ForumUsecase.RedeliverWebhook(slug, id, deliveryId, authutils.GetNickname(r))
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, createdDelivery)
+}
+
+func (uh *ForumHandler) GetForumSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slug := mux.Vars(r)["slug"]
+
+	findedSettings, code, err := uh.ForumUsecase.GetForumSettings(slug, authutils.GetNickname(r))
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, findedSettings)
+}
+
+func (uh *ForumHandler) UpdateForumSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slug := mux.Vars(r)["slug"]
+
+	var newSettings models.ForumSettings
+	err := ioutils.ReadJSON(r, &newSettings)
+	if err != nil {
+		ioutils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updatedSettings, code, err := uh.ForumUsecase.UpdateForumSettings(slug, authutils.GetNickname(r), newSettings)
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, updatedSettings)
+}
+
 func (uh *ForumHandler) CreateForumThreadHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -58,7 +287,7 @@ func (uh *ForumHandler) CreateForumThreadHandler(w http.ResponseWriter, r *http.
 	}
 
 	createdThread, code, err := uh.ForumUsecase.CreateThread(slug, newThread)
-	if code == http.StatusNotFound {
+	if err != nil {
 		ioutils.SendError(w, code, err.Error())
 		return
 	}
@@ -71,7 +300,7 @@ func (uh *ForumHandler) GetForumUsersHandler(w http.ResponseWriter, r *http.Requ
 
 	slug := mux.Vars(r)["slug"]
 
-	findedUsers, code, err := uh.ForumUsecase.GetForumUsers(slug, r.URL.Query())
+	findedUsers, code, err := uh.ForumUsecase.GetForumUsers(slug, authutils.GetNickname(r), r.URL.Query())
 	if err != nil {
 		ioutils.SendError(w, code, err.Error())
 		return
@@ -85,7 +314,7 @@ func (uh *ForumHandler) GetForumThreadsHandler(w http.ResponseWriter, r *http.Re
 
 	slug := mux.Vars(r)["slug"]
 
-	findedThreads, code, err := uh.ForumUsecase.GetThreads(slug, r.URL.Query())
+	findedThreads, code, err := uh.ForumUsecase.GetThreads(slug, authutils.GetNickname(r), r.URL.Query())
 	if err != nil || code == http.StatusNotFound {
 		ioutils.SendError(w, code, err.Error())
 		return
@@ -94,12 +323,50 @@ func (uh *ForumHandler) GetForumThreadsHandler(w http.ResponseWriter, r *http.Re
 	ioutils.Send(w, code, findedThreads)
 }
 
+func (uh *ForumHandler) GetForumTagsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slug := mux.Vars(r)["slug"]
+
+	findedTags, code, err := uh.ForumUsecase.GetForumTags(slug, authutils.GetNickname(r), r.URL.Query())
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, findedTags)
+}
+
+func (uh *ForumHandler) SearchHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	findedResults, code, err := uh.ForumUsecase.Search(authutils.GetNickname(r), r.URL.Query())
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, findedResults)
+}
+
+func (uh *ForumHandler) GetUsersHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	findedUsers, code, err := uh.ForumUsecase.SearchUsers(authutils.GetNickname(r), r.URL.Query())
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, findedUsers)
+}
+
 func (uh *ForumHandler) PostDetailsHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	id := mux.Vars(r)["id"]
 
-	findedPostIndo, code, err := uh.ForumUsecase.GetPostInfo(id, r.URL.Query())
+	findedPostIndo, code, err := uh.ForumUsecase.GetPostInfo(id, authutils.GetNickname(r), r.URL.Query())
 	if err != nil {
 		ioutils.SendError(w, code, err.Error())
 		return
@@ -129,6 +396,41 @@ func (uh *ForumHandler) EditPostHandler(w http.ResponseWriter, r *http.Request)
 	ioutils.Send(w, code, updatedPost)
 }
 
+func (uh *ForumHandler) DeletePostHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id := mux.Vars(r)["id"]
+
+	updatedThread, code, err := uh.ForumUsecase.DeletePost(id, authutils.GetNickname(r))
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, updatedThread)
+}
+
+func (uh *ForumHandler) SplitPostHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id := mux.Vars(r)["id"]
+
+	var splitData models.ThreadSplit
+	err := ioutils.ReadJSON(r, &splitData)
+	if err != nil {
+		ioutils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	createdThread, code, err := uh.ForumUsecase.SplitThread(id, authutils.GetNickname(r), splitData)
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, createdThread)
+}
+
 func (uh *ForumHandler) ServiceClearHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -179,7 +481,21 @@ func (uh *ForumHandler) ThreadDetailsHandler(w http.ResponseWriter, r *http.Requ
 
 	slugOrId := mux.Vars(r)["slug_or_id"]
 
-	findedThread, code, err := uh.ForumUsecase.FindThreadBySlugOrId(slugOrId)
+	findedThread, code, err := uh.ForumUsecase.FindThreadBySlugOrId(slugOrId, authutils.GetNickname(r))
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, findedThread)
+}
+
+func (uh *ForumHandler) SubscribeThreadHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slugOrId := mux.Vars(r)["slug_or_id"]
+
+	findedThread, code, err := uh.ForumUsecase.SubscribeThread(slugOrId, authutils.GetNickname(r), r.Method != http.MethodDelete)
 	if err != nil {
 		ioutils.SendError(w, code, err.Error())
 		return
@@ -188,6 +504,51 @@ func (uh *ForumHandler) ThreadDetailsHandler(w http.ResponseWriter, r *http.Requ
 	ioutils.Send(w, code, findedThread)
 }
 
+func (uh *ForumHandler) MarkThreadReadHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slugOrId := mux.Vars(r)["slug_or_id"]
+
+	var readData models.ThreadRead
+	if r.ContentLength != 0 {
+		err := ioutils.ReadJSON(r, &readData)
+		if err != nil {
+			ioutils.SendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	threadRead, code, err := uh.ForumUsecase.MarkThreadRead(slugOrId, authutils.GetNickname(r), readData)
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, threadRead)
+}
+
+func (uh *ForumHandler) ThreadEventsHandler(w http.ResponseWriter, r *http.Request) {
+	slugOrId := mux.Vars(r)["slug_or_id"]
+
+	// EventSource sends Last-Event-ID on reconnects only, the query parameter
+	// lets clients resume after a page reload
+	lastEventId := r.Header.Get("Last-Event-ID")
+	if lastEventId == "" {
+		lastEventId = r.URL.Query().Get("last_event_id")
+	}
+	afterId, _ := strconv.ParseInt(lastEventId, 10, 64)
+
+	stream, code, err := uh.ForumUsecase.StreamThreadEvents(slugOrId, authutils.GetNickname(r), afterId)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+	defer stream.Close()
+
+	ioutils.SendEvents(w, r, stream)
+}
+
 func (uh *ForumHandler) UpdateThreadHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -209,12 +570,75 @@ func (uh *ForumHandler) UpdateThreadHandler(w http.ResponseWriter, r *http.Reque
 	ioutils.Send(w, code, updatedThread)
 }
 
+func (uh *ForumHandler) PinThreadHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slugOrId := mux.Vars(r)["slug_or_id"]
+
+	var pinData models.ThreadPin
+	err := ioutils.ReadJSON(r, &pinData)
+	if err != nil {
+		ioutils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pinnedThread, code, err := uh.ForumUsecase.PinThread(slugOrId, authutils.GetNickname(r), pinData)
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, pinnedThread)
+}
+
+func (uh *ForumHandler) MoveThreadHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slugOrId := mux.Vars(r)["slug_or_id"]
+
+	var moveData models.ThreadMove
+	err := ioutils.ReadJSON(r, &moveData)
+	if err != nil {
+		ioutils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	movedThread, code, err := uh.ForumUsecase.MoveThread(slugOrId, authutils.GetNickname(r), moveData)
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, movedThread)
+}
+
+func (uh *ForumHandler) MergeThreadsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slugOrId := mux.Vars(r)["slug_or_id"]
+
+	var mergeData models.ThreadMerge
+	err := ioutils.ReadJSON(r, &mergeData)
+	if err != nil {
+		ioutils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mergedThread, code, err := uh.ForumUsecase.MergeThreads(slugOrId, authutils.GetNickname(r), mergeData)
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, mergedThread)
+}
+
 func (uh *ForumHandler) GetThreadsPostsHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	slugOrId := mux.Vars(r)["slug_or_id"]
 
-	findedPosts, code, err := uh.ForumUsecase.GetPosts(slugOrId, r.URL.Query())
+	findedPosts, code, err := uh.ForumUsecase.GetPosts(slugOrId, authutils.GetNickname(r), r.URL.Query())
 	if err != nil {
 		ioutils.SendError(w, code, err.Error())
 		return
@@ -244,6 +668,99 @@ func (uh *ForumHandler) VoteThreadHandler(w http.ResponseWriter, r *http.Request
 	ioutils.Send(w, code, threadInfo)
 }
 
+func (uh *ForumHandler) GetThreadVotesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	slugOrId := mux.Vars(r)["slug_or_id"]
+
+	threadVotes, code, err := uh.ForumUsecase.GetThreadVotes(slugOrId, authutils.GetNickname(r), r.URL.Query())
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, threadVotes)
+}
+
+func (uh *ForumHandler) GetUserVotesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	nickname := mux.Vars(r)["nickname"]
+
+	findedVotes, code, err := uh.ForumUsecase.GetUserVotes(nickname, authutils.GetNickname(r), r.URL.Query())
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, findedVotes)
+}
+
+func (uh *ForumHandler) VotePostHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id := mux.Vars(r)["id"]
+
+	var newVote models.Vote
+	err := ioutils.ReadJSON(r, &newVote)
+	if err != nil {
+		ioutils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	postInfo, code, err := uh.ForumUsecase.VotePost(id, authutils.GetNickname(r), newVote)
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, postInfo)
+}
+
+func (uh *ForumHandler) AddPostReactionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id := mux.Vars(r)["id"]
+	emoji := mux.Vars(r)["emoji"]
+
+	postInfo, code, err := uh.ForumUsecase.AddPostReaction(id, emoji, authutils.GetNickname(r))
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, postInfo)
+}
+
+func (uh *ForumHandler) DeletePostReactionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id := mux.Vars(r)["id"]
+	emoji := mux.Vars(r)["emoji"]
+
+	postInfo, code, err := uh.ForumUsecase.DeletePostReaction(id, emoji, authutils.GetNickname(r))
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, postInfo)
+}
+
+func (uh *ForumHandler) GetPostReactionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id := mux.Vars(r)["id"]
+
+	findedReactions, code, err := uh.ForumUsecase.GetPostReactions(id, authutils.GetNickname(r))
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, findedReactions)
+}
+
 func (uh *ForumHandler) CreateUserHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -283,6 +800,120 @@ func (uh *ForumHandler) GetUserProfileHandler(w http.ResponseWriter, r *http.Req
 	ioutils.Send(w, code, findedUser)
 }
 
+func (uh *ForumHandler) GetUserMentionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	nickname := mux.Vars(r)["nickname"]
+
+	findedPosts, code, err := uh.ForumUsecase.GetUserMentions(nickname, authutils.GetNickname(r), r.URL.Query())
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, findedPosts)
+}
+
+func (uh *ForumHandler) GetNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	nickname := mux.Vars(r)["nickname"]
+
+	findedNotifications, code, err := uh.ForumUsecase.GetNotifications(nickname, authutils.GetNickname(r), r.URL.Query())
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, findedNotifications)
+}
+
+func (uh *ForumHandler) CountNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	nickname := mux.Vars(r)["nickname"]
+
+	notificationCount, code, err := uh.ForumUsecase.CountNotifications(nickname, authutils.GetNickname(r))
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, notificationCount)
+}
+
+func (uh *ForumHandler) MarkNotificationsReadHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	nickname := mux.Vars(r)["nickname"]
+
+	var readData models.NotificationsRead
+	if r.ContentLength != 0 {
+		err := ioutils.ReadJSON(r, &readData)
+		if err != nil {
+			ioutils.SendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	notificationCount, code, err := uh.ForumUsecase.MarkNotificationsRead(nickname, authutils.GetNickname(r), readData)
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, notificationCount)
+}
+
+func (uh *ForumHandler) GetNotificationPreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	nickname := mux.Vars(r)["nickname"]
+
+	findedPreferences, code, err := uh.ForumUsecase.GetNotificationPreferences(nickname, authutils.GetNickname(r))
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, findedPreferences)
+}
+
+func (uh *ForumHandler) UpdateNotificationPreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	nickname := mux.Vars(r)["nickname"]
+
+	var newPreferences models.NotificationPreferences
+	err := ioutils.ReadJSON(r, &newPreferences)
+	if err != nil {
+		ioutils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updatedPreferences, code, err := uh.ForumUsecase.UpdateNotificationPreferences(nickname, authutils.GetNickname(r), newPreferences)
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, updatedPreferences)
+}
+
+func (uh *ForumHandler) GetFeedHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	nickname := mux.Vars(r)["nickname"]
+
+	findedPosts, code, err := uh.ForumUsecase.GetFeed(nickname, authutils.GetNickname(r), r.URL.Query())
+	if err != nil {
+		ioutils.SendError(w, code, err.Error())
+		return
+	}
+
+	ioutils.Send(w, code, findedPosts)
+}
+
 func (uh *ForumHandler) UpdateUserProfileHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
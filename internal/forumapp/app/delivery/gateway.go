@@ -0,0 +1,229 @@
+package delivery
+
+import (
+	"forumApp/internal/forumapp/models"
+	"forumApp/internal/pkg/authutils"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	gatewayMaxSubscriptions = 20
+	gatewayBufferSize       = 256
+	gatewayMaxMessageSize   = 1024
+	gatewayPingPeriod       = 30 * time.Second
+	gatewayPongWait         = 60 * time.Second
+	gatewayWriteWait        = 10 * time.Second
+)
+
+var gatewayUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+type gatewaySubscription struct {
+	stream    models.EventStream
+	cancelled chan struct{}
+}
+
+// gatewayConn is a WebSocket client subscribed to several event channels.
+// Everything it is sent goes through out; a client that doesn't read fast
+// enough to keep it from filling up is disconnected.
+type gatewayConn struct {
+	conn          *websocket.Conn
+	usecase       models.ForumUsecase
+	actor         string
+	out           chan []byte
+	done          chan struct{}
+	closeOnce     sync.Once
+	closeCode     int
+	closeText     string
+	subscriptions map[string]gatewaySubscription
+}
+
+func (uh *ForumHandler) GatewayHandler(w http.ResponseWriter, r *http.Request) {
+	conn, err := gatewayUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	gc := &gatewayConn{
+		conn:          conn,
+		usecase:       uh.ForumUsecase,
+		actor:         authutils.GetNickname(r),
+		out:           make(chan []byte, gatewayBufferSize),
+		done:          make(chan struct{}),
+		subscriptions: make(map[string]gatewaySubscription),
+	}
+
+	go gc.writeLoop()
+	gc.readLoop()
+}
+
+func (gc *gatewayConn) readLoop() {
+	defer func() {
+		for channel := range gc.subscriptions {
+			gc.unsubscribe(channel)
+		}
+		gc.close(websocket.CloseNormalClosure, "")
+	}()
+
+	gc.conn.SetReadLimit(gatewayMaxMessageSize)
+	_ = gc.conn.SetReadDeadline(time.Now().Add(gatewayPongWait))
+	gc.conn.SetPongHandler(func(string) error {
+		return gc.conn.SetReadDeadline(time.Now().Add(gatewayPongWait))
+	})
+
+	for {
+		_, message, err := gc.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var request models.GatewayRequest
+		err = request.UnmarshalJSON(message)
+		if err != nil {
+			gc.reply(models.GatewayReply{Type: "error", Message: err.Error()})
+			continue
+		}
+
+		switch request.Action {
+		case "subscribe":
+			gc.subscribe(request)
+		case "unsubscribe":
+			if _, ok := gc.subscriptions[request.Channel]; ok {
+				gc.unsubscribe(request.Channel)
+			}
+			gc.reply(models.GatewayReply{Type: "unsubscribed", Channel: request.Channel})
+		default:
+			gc.reply(models.GatewayReply{Type: "error", Message: "unknown action " + request.Action})
+		}
+	}
+}
+
+func (gc *gatewayConn) writeLoop() {
+	ping := time.NewTicker(gatewayPingPeriod)
+	defer func() {
+		ping.Stop()
+		gc.conn.Close()
+	}()
+
+	for {
+		select {
+		case <-gc.done:
+			_ = gc.conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(gc.closeCode, gc.closeText),
+				time.Now().Add(gatewayWriteWait),
+			)
+			return
+		case message := <-gc.out:
+			_ = gc.conn.SetWriteDeadline(time.Now().Add(gatewayWriteWait))
+			if err := gc.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				gc.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ping.C:
+			_ = gc.conn.SetWriteDeadline(time.Now().Add(gatewayWriteWait))
+			if err := gc.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				gc.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		}
+	}
+}
+
+// Subscriptions are keyed by the channel name the client used, so that it can
+// unsubscribe with the same name; events carry the resolved channel name.
+func (gc *gatewayConn) subscribe(request models.GatewayRequest) {
+	if _, ok := gc.subscriptions[request.Channel]; ok {
+		gc.reply(models.GatewayReply{Type: "subscribed", Channel: request.Channel})
+		return
+	}
+	if len(gc.subscriptions) >= gatewayMaxSubscriptions {
+		gc.reply(models.GatewayReply{Type: "error", Channel: request.Channel, Message: "too many subscriptions"})
+		return
+	}
+
+	stream, _, err := gc.usecase.SubscribeChannel(request.Channel, gc.actor, request.LastEventId)
+	if err != nil {
+		gc.reply(models.GatewayReply{Type: "error", Channel: request.Channel, Message: err.Error()})
+		return
+	}
+
+	subscription := gatewaySubscription{
+		stream:    stream,
+		cancelled: make(chan struct{}),
+	}
+	gc.subscriptions[request.Channel] = subscription
+	gc.reply(models.GatewayReply{Type: "subscribed", Channel: stream.Channel})
+
+	go gc.forward(subscription)
+}
+
+func (gc *gatewayConn) unsubscribe(channel string) {
+	subscription := gc.subscriptions[channel]
+	delete(gc.subscriptions, channel)
+	close(subscription.cancelled)
+	subscription.stream.Close()
+}
+
+func (gc *gatewayConn) forward(subscription gatewaySubscription) {
+	// live events may come out of the order of ids, so the ones already sent
+	// with the backlog are skipped by id
+	sentEvents := make(map[int64]struct{}, len(subscription.stream.Backlog))
+	for _, event := range subscription.stream.Backlog {
+		gc.sendEvent(event)
+		sentEvents[event.Id] = struct{}{}
+	}
+
+	for event := range subscription.stream.Events {
+		if _, ok := sentEvents[event.Id]; ok {
+			continue
+		}
+		gc.sendEvent(event)
+	}
+
+	select {
+	case <-subscription.cancelled:
+	default:
+		// the broker dropped the subscription because it fell behind
+		gc.close(websocket.CloseTryAgainLater, "too slow")
+	}
+}
+
+func (gc *gatewayConn) sendEvent(event models.StreamEvent) {
+	message, err := event.MarshalJSON()
+	if err != nil {
+		return
+	}
+	gc.send(message)
+}
+
+func (gc *gatewayConn) reply(reply models.GatewayReply) {
+	message, err := reply.MarshalJSON()
+	if err != nil {
+		return
+	}
+	gc.send(message)
+}
+
+func (gc *gatewayConn) send(message []byte) {
+	select {
+	case <-gc.done:
+	case gc.out <- message:
+	default:
+		gc.close(websocket.CloseTryAgainLater, "too slow")
+	}
+}
+
+func (gc *gatewayConn) close(code int, text string) {
+	gc.closeOnce.Do(func() {
+		gc.closeCode = code
+		gc.closeText = text
+		close(gc.done)
+	})
+}
@@ -11,14 +11,39 @@ func SetUserRouting(router *mux.Router, us models.ForumUsecase) {
 		ForumUsecase: us,
 	}
 
+	router.HandleFunc("/api/forums", forumHandler.GetForumsHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/forums/tree", forumHandler.GetForumTreeHandler).Methods("GET", "OPTIONS")
 	router.HandleFunc("/api/forum/create", forumHandler.CreateForumHandler).Methods("POST", "OPTIONS")
 	router.HandleFunc("/api/forum/{slug}/details", forumHandler.ForumDetailsHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/forum/{slug}/details", forumHandler.UpdateForumHandler).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/forum/{slug}/details", forumHandler.DeleteForumHandler).Methods("DELETE", "OPTIONS")
+	router.HandleFunc("/api/forum/{slug}/settings", forumHandler.GetForumSettingsHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/forum/{slug}/settings", forumHandler.UpdateForumSettingsHandler).Methods("POST", "OPTIONS")
 	router.HandleFunc("/api/forum/{slug}/create", forumHandler.CreateForumThreadHandler).Methods("POST", "OPTIONS")
 	router.HandleFunc("/api/forum/{slug}/users", forumHandler.GetForumUsersHandler).Methods("GET", "OPTIONS")
 	router.HandleFunc("/api/forum/{slug}/threads", forumHandler.GetForumThreadsHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/forum/{slug}/tags", forumHandler.GetForumTagsHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/forum/{slug}/stats", forumHandler.GetForumStatsHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/leaderboard", forumHandler.GetLeaderboardHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/trending", forumHandler.GetTrendingHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/forum/{slug}/subscribe", forumHandler.SubscribeForumHandler).Methods("POST", "DELETE", "OPTIONS")
+	router.HandleFunc("/api/forum/{slug}/webhooks", forumHandler.GetWebhooksHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/forum/{slug}/webhooks", forumHandler.CreateWebhookHandler).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/forum/{slug}/webhooks/{id}", forumHandler.DeleteWebhookHandler).Methods("DELETE", "OPTIONS")
+	router.HandleFunc("/api/forum/{slug}/webhooks/{id}/deliveries", forumHandler.GetWebhookDeliveriesHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/forum/{slug}/webhooks/{id}/deliveries/{delivery_id}/redeliver", forumHandler.RedeliverWebhookHandler).Methods("POST", "OPTIONS")
 
 	router.HandleFunc("/api/post/{id}/details", forumHandler.PostDetailsHandler).Methods("GET", "OPTIONS")
 	router.HandleFunc("/api/post/{id}/details", forumHandler.EditPostHandler).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/post/{id}/details", forumHandler.DeletePostHandler).Methods("DELETE", "OPTIONS")
+	router.HandleFunc("/api/post/{id}/split", forumHandler.SplitPostHandler).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/post/{id}/vote", forumHandler.VotePostHandler).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/post/{id}/reactions", forumHandler.GetPostReactionsHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/post/{id}/reactions/{emoji}", forumHandler.AddPostReactionHandler).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/post/{id}/reactions/{emoji}", forumHandler.DeletePostReactionHandler).Methods("DELETE", "OPTIONS")
+
+	router.HandleFunc("/api/search", forumHandler.SearchHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/gateway", forumHandler.GatewayHandler).Methods("GET")
 
 	router.HandleFunc("/api/service/clear", forumHandler.ServiceClearHandler).Methods("POST", "OPTIONS")
 	router.HandleFunc("/api/service/status", forumHandler.ServiceStatusHandler).Methods("GET", "OPTIONS")
@@ -28,8 +53,24 @@ func SetUserRouting(router *mux.Router, us models.ForumUsecase) {
 	router.HandleFunc("/api/thread/{slug_or_id}/details", forumHandler.UpdateThreadHandler).Methods("POST", "OPTIONS")
 	router.HandleFunc("/api/thread/{slug_or_id}/posts", forumHandler.GetThreadsPostsHandler).Methods("GET", "OPTIONS")
 	router.HandleFunc("/api/thread/{slug_or_id}/vote", forumHandler.VoteThreadHandler).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/thread/{slug_or_id}/votes", forumHandler.GetThreadVotesHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/thread/{slug_or_id}/pin", forumHandler.PinThreadHandler).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/thread/{slug_or_id}/move", forumHandler.MoveThreadHandler).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/thread/{slug_or_id}/merge", forumHandler.MergeThreadsHandler).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/thread/{slug_or_id}/subscribe", forumHandler.SubscribeThreadHandler).Methods("POST", "DELETE", "OPTIONS")
+	router.HandleFunc("/api/thread/{slug_or_id}/read", forumHandler.MarkThreadReadHandler).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/thread/{slug_or_id}/events", forumHandler.ThreadEventsHandler).Methods("GET", "OPTIONS")
 
 	router.HandleFunc("/api/user/{nickname}/create", forumHandler.CreateUserHandler).Methods("POST", "OPTIONS")
 	router.HandleFunc("/api/user/{nickname}/profile", forumHandler.GetUserProfileHandler).Methods("GET", "OPTIONS")
 	router.HandleFunc("/api/user/{nickname}/profile", forumHandler.UpdateUserProfileHandler).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/user/{nickname}/mentions", forumHandler.GetUserMentionsHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/user/{nickname}/votes", forumHandler.GetUserVotesHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/user/{nickname}/feed", forumHandler.GetFeedHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/user/{nickname}/notifications", forumHandler.GetNotificationsHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/user/{nickname}/notifications/count", forumHandler.CountNotificationsHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/user/{nickname}/notifications/read", forumHandler.MarkNotificationsReadHandler).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/user/{nickname}/notifications/preferences", forumHandler.GetNotificationPreferencesHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/user/{nickname}/notifications/preferences", forumHandler.UpdateNotificationPreferencesHandler).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/users", forumHandler.GetUsersHandler).Methods("GET", "OPTIONS")
 }
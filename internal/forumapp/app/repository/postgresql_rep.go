@@ -1,16 +1,19 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"forumApp/configs"
 	"forumApp/internal/forumapp/models"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/jackc/pgx"
+	"github.com/mailru/easyjson"
 )
 
 type PostgreForumRepo struct {
@@ -43,9 +46,46 @@ func NewPostgresUserRepository(config configs.PostgresConfig) (models.ForumRepos
 	return &PostgreForumRepo{pool}, nil
 }
 
+func forumFields(forum *models.Forum) []interface{} {
+	return []interface{}{
+		&forum.Id,
+		&forum.Title,
+		&forum.User,
+		&forum.Slug,
+		&forum.Posts,
+		&forum.Threads,
+		&forum.Description,
+		&forum.Parent,
+		&forum.Position,
+		&forum.Category,
+	}
+}
+
+func threadFields(thread *models.Thread) []interface{} {
+	return []interface{}{
+		&thread.Id,
+		&thread.Title,
+		&thread.Author,
+		&thread.Forum,
+		&thread.Message,
+		&thread.Votes,
+		&thread.Slug,
+		&thread.Created,
+		&thread.Pinned,
+		&thread.PinOrder,
+		&thread.Announcement,
+		&thread.Posts,
+		&thread.LastPostId,
+		&thread.LastPostAuthor,
+		&thread.LastPostAt,
+		&thread.MovedTo,
+		&thread.Tags,
+	}
+}
+
 func (pfr *PostgreForumRepo) FindUserByNickname(nickname string) (models.User, error) {
 	var findedUser models.User
-	err := pfr.Conn.QueryRow(FindUserByNicknameQuery, nickname).Scan(&findedUser.Id, &findedUser.Nickname, &findedUser.About, &findedUser.Email, &findedUser.Fullname)
+	err := pfr.Conn.QueryRow(FindUserByNicknameQuery, nickname).Scan(&findedUser.Id, &findedUser.Nickname, &findedUser.About, &findedUser.Email, &findedUser.Fullname, &findedUser.IsAdmin, &findedUser.Created)
 	if err != nil {
 		return models.User{}, err
 	}
@@ -91,8 +131,14 @@ func (pfr *PostgreForumRepo) CreateUser(userData models.User) (models.User, erro
 }
 
 func (pfr *PostgreForumRepo) UpdateUser(userData models.User) (models.User, error) {
+	tx, err := pfr.Conn.Begin()
+	if err != nil {
+		return models.User{}, err
+	}
+	defer tx.Rollback()
+
 	var updatedUser models.User
-	err := pfr.Conn.QueryRow(
+	err = tx.QueryRow(
 		UpdateUserQuery,
 		userData.Nickname,
 		userData.Fullname,
@@ -107,6 +153,16 @@ func (pfr *PostgreForumRepo) UpdateUser(userData models.User) (models.User, erro
 	if err != nil {
 		return models.User{}, err
 	}
+
+	err = addOutboxEvent(tx, "user", updatedUser.Nickname, models.OutboxUserUpdated, updatedUser)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.User{}, err
+	}
 	return updatedUser, nil
 }
 
@@ -117,12 +173,20 @@ func (pfr *PostgreForumRepo) CreateForum(forumData models.Forum) (models.Forum,
 		forumData.Title,
 		forumData.User,
 		forumData.Slug,
+		forumData.Description,
+		forumData.Parent,
+		forumData.Position,
+		forumData.Category,
 	).Scan(
 		&createdForum.Title,
 		&createdForum.User,
 		&createdForum.Slug,
 		&createdForum.Posts,
 		&createdForum.Threads,
+		&createdForum.Description,
+		&createdForum.Parent,
+		&createdForum.Position,
+		&createdForum.Category,
 	)
 	if err != nil {
 		return models.Forum{}, err
@@ -135,35 +199,194 @@ func (pfr *PostgreForumRepo) FindForumBySlug(slug string) (models.Forum, error)
 	err := pfr.Conn.QueryRow(
 		FindForumBySlugQuery,
 		slug,
-	).Scan(
-		&findedForum.Id,
-		&findedForum.Title,
-		&findedForum.User,
-		&findedForum.Slug,
-		&findedForum.Posts,
-		&findedForum.Threads,
-	)
+	).Scan(forumFields(&findedForum)...)
 	if err != nil {
 		return models.Forum{}, err
 	}
 	return findedForum, nil
 }
 
+var forumSortKeys = map[string]string{
+	"name":     "title",
+	"activity": "last_activity",
+	"posts":    "posts",
+}
+
+func (pfr *PostgreForumRepo) FindForums(limit string, since string, sort string, desc string, comparisonSign string) ([]models.Forum, error) {
+	findedForums := make([]models.Forum, 0)
+	sortKey, ok := forumSortKeys[sort]
+	if !ok {
+		return []models.Forum{}, errors.New("undefined sort type")
+	}
+
+	var values []interface{}
+	sqlQuery := FindForumsQuery
+	if since != "" {
+		sqlQuery += fmt.Sprintf(" AND (%s, id) %s (SELECT %s, id FROM forums WHERE slug = $1)", sortKey, comparisonSign, sortKey)
+		values = append(values, since)
+	}
+	sqlQuery += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT %s;", sortKey, desc, desc, limit)
+
+	rows, err := pfr.Conn.Query(sqlQuery, values...)
+	if err != nil {
+		return []models.Forum{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curForum models.Forum
+		err := rows.Scan(forumFields(&curForum)...)
+		if err != nil {
+			return []models.Forum{}, err
+		}
+		findedForums = append(findedForums, curForum)
+	}
+	return findedForums, nil
+}
+
+func (pfr *PostgreForumRepo) FindForumTree() ([]models.Forum, error) {
+	findedForums := make([]models.Forum, 0)
+	rows, err := pfr.Conn.Query(FindForumTreeQuery)
+	if err != nil {
+		return []models.Forum{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curForum models.Forum
+		err := rows.Scan(forumFields(&curForum)...)
+		if err != nil {
+			return []models.Forum{}, err
+		}
+		findedForums = append(findedForums, curForum)
+	}
+	return findedForums, nil
+}
+
+func (pfr *PostgreForumRepo) IsForumInSubtree(rootSlug string, slug string) (bool, error) {
+	var inSubtree bool
+	err := pfr.Conn.QueryRow(IsForumInSubtreeQuery, rootSlug, slug).Scan(&inSubtree)
+	if err != nil {
+		return false, err
+	}
+	return inSubtree, nil
+}
+
+func (pfr *PostgreForumRepo) UpdateForum(forumData models.Forum) (models.Forum, error) {
+	var updatedForum models.Forum
+	err := pfr.Conn.QueryRow(
+		UpdateForumQuery,
+		forumData.Id,
+		forumData.Title,
+		forumData.Description,
+		forumData.Parent,
+		forumData.Position,
+	).Scan(forumFields(&updatedForum)...)
+	if err != nil {
+		return models.Forum{}, err
+	}
+	return updatedForum, nil
+}
+
+func (pfr *PostgreForumRepo) DeleteForum(forumData models.Forum) error {
+	tx, err := pfr.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(DeleteForumVotesQuery, forumData.Slug)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(DeleteForumPostsQuery, forumData.Slug)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(DeleteForumThreadsQuery, forumData.Slug)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(DeleteForumUsersQuery, forumData.Id)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ReparentForumChildrenQuery, forumData.Slug, forumData.Parent)
+	if err != nil {
+		return err
+	}
+
+	// The forum row itself is only archived so that its slug stays taken.
+	_, err = tx.Exec(ArchiveForumQuery, forumData.Id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (pfr *PostgreForumRepo) GetForumSettings(forumId int64) (models.ForumSettings, error) {
+	var findedSettings models.ForumSettings
+	err := pfr.Conn.QueryRow(
+		GetForumSettingsQuery,
+		forumId,
+	).Scan(
+		&findedSettings.Description,
+		&findedSettings.Rules,
+		&findedSettings.ReadOnly,
+		&findedSettings.AnonymousRead,
+		&findedSettings.MinAccountAge,
+		&findedSettings.MaxPostLength,
+		&findedSettings.AllowedTags,
+		&findedSettings.AnonymousVotes,
+		&findedSettings.MinReputation,
+	)
+	if err != nil {
+		return models.ForumSettings{}, err
+	}
+	return findedSettings, nil
+}
+
+func (pfr *PostgreForumRepo) UpdateForumSettings(forumId int64, settings models.ForumSettings) error {
+	tx, err := pfr.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(UpdateForumDescriptionQuery, forumId, settings.Description)
+	if err != nil {
+		return err
+	}
+
+	if settings.AllowedTags == nil {
+		settings.AllowedTags = []string{}
+	}
+	_, err = tx.Exec(
+		UpdateForumSettingsQuery,
+		forumId,
+		settings.Rules,
+		settings.ReadOnly,
+		settings.AnonymousRead,
+		settings.MinAccountAge,
+		settings.MaxPostLength,
+		settings.AllowedTags,
+		settings.AnonymousVotes,
+		settings.MinReputation,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (pfr *PostgreForumRepo) FindThreadBySlug(slug string) (models.Thread, error) {
 	var findedThread models.Thread
 	err := pfr.Conn.QueryRow(
 		FindThreadBySlugQuery,
 		slug,
-	).Scan(
-		&findedThread.Id,
-		&findedThread.Title,
-		&findedThread.Author,
-		&findedThread.Forum,
-		&findedThread.Message,
-		&findedThread.Votes,
-		&findedThread.Slug,
-		&findedThread.Created,
-	)
+	).Scan(threadFields(&findedThread)...)
 	if err != nil {
 		return models.Thread{}, err
 	}
@@ -175,7 +398,17 @@ func (pfr *PostgreForumRepo) CreateThread(threadData models.Thread) (models.Thre
 	if threadData.Created.String() == "" {
 		threadData.Created = time.Now()
 	}
-	err := pfr.Conn.QueryRow(
+	if threadData.Tags == nil {
+		threadData.Tags = []string{}
+	}
+
+	tx, err := pfr.Conn.Begin()
+	if err != nil {
+		return models.Thread{}, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(
 		CreateThreadQuery,
 		threadData.Title,
 		threadData.Author,
@@ -183,53 +416,148 @@ func (pfr *PostgreForumRepo) CreateThread(threadData models.Thread) (models.Thre
 		threadData.Message,
 		threadData.Slug,
 		threadData.Created,
-	).Scan(
-		&createdThread.Id,
-		&createdThread.Title,
-		&createdThread.Author,
-		&createdThread.Forum,
-		&createdThread.Message,
-		&createdThread.Votes,
-		&createdThread.Slug,
-		&createdThread.Created,
-	)
+		threadData.Tags,
+	).Scan(threadFields(&createdThread)...)
 	if err != nil {
 		return models.Thread{}, err
 	}
 
 	var forumId int64
-	err = pfr.Conn.QueryRow(UpdateForumsThreadCountQuery, threadData.Forum).Scan(&forumId)
+	err = tx.QueryRow(UpdateForumsThreadCountQuery, threadData.Forum).Scan(&forumId)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	err = addThreadCreatedEvents(tx, forumId, createdThread)
 	if err != nil {
 		return models.Thread{}, err
 	}
 
+	err = tx.Commit()
+	if err != nil {
+		return models.Thread{}, err
+	}
 	return createdThread, nil
 }
 
-func (pfr *PostgreForumRepo) FindThreadsBySlugWithParams(slug string, limit string, since string, desc string, comparisonSign string) ([]models.Thread, error) {
+// addThreadCreatedEvents subscribes the author to the new thread and records
+// its outbox event and webhook deliveries in the transaction that creates it.
+func addThreadCreatedEvents(tx *pgx.Tx, forumId int64, thread models.Thread) error {
+	// authors watch their threads
+	_, err := tx.Exec(SubscribeThreadQuery, []string{thread.Author}, thread.Id)
+	if err != nil {
+		return err
+	}
+
+	err = addOutboxEvent(tx, "thread", strconv.FormatInt(thread.Id, 10), models.OutboxThreadCreated, thread)
+	if err != nil {
+		return err
+	}
+
+	return enqueueWebhookDeliveries(tx, forumId, []models.WebhookEvent{{
+		Event:  models.WebhookThreadCreated,
+		Forum:  thread.Forum,
+		Thread: &thread,
+	}})
+}
+
+// addPostsCreatedEvents records the outbox events and webhook deliveries of
+// the new posts of the thread in the transaction that creates them.
+func addPostsCreatedEvents(tx *pgx.Tx, forumId int64, thread models.Thread, posts []models.Post) error {
+	webhookEvents := make([]models.WebhookEvent, 0, len(posts))
+	for i, post := range posts {
+		err := addOutboxEvent(tx, "post", strconv.FormatInt(post.Id, 10), models.OutboxPostCreated, post)
+		if err != nil {
+			return err
+		}
+		webhookEvents = append(webhookEvents, models.WebhookEvent{
+			Event:  models.WebhookPostCreated,
+			Forum:  thread.Forum,
+			Thread: &thread,
+			Post:   &posts[i],
+		})
+	}
+	return enqueueWebhookDeliveries(tx, forumId, webhookEvents)
+}
+
+var threadSortKeys = map[string]string{
+	"last_post": "last_post_at",
+	"votes":     "votes",
+	"replies":   "posts",
+	"hot":       "thread_hot(votes, posts, created)",
+}
+
+func (pfr *PostgreForumRepo) FindThreadsBySlugWithParams(slug string, limit string, since string, sort string, desc string, comparisonSign string, withSubforums bool, tags []string, matchAllTags bool) ([]models.Thread, error) {
 	findedThreads := make([]models.Thread, 0)
 	customizeQuery := FindThreadsByForumQuery
-	if since != "" {
-		customizeQuery += fmt.Sprintf(" AND created %s '%s'", comparisonSign, since)
+	if withSubforums {
+		customizeQuery = FindThreadsByForumTreeQuery
+	}
+	values := []interface{}{slug}
+	if len(tags) > 0 {
+		if matchAllTags {
+			customizeQuery += " AND tags @> $2"
+		} else {
+			customizeQuery += " AND tags && $2"
+		}
+		values = append(values, tags)
+	}
+	sortKey, ok := threadSortKeys[sort]
+	if !ok {
+		if since != "" {
+			customizeQuery += fmt.Sprintf(" AND created %s '%s'", comparisonSign, since)
+		}
+		customizeQuery += fmt.Sprintf(" ORDER BY created %s LIMIT %s;", desc, limit)
+	} else {
+		// since holds the id of the last thread of the previous page: the
+		// (key, id) pair keeps pages stable when several threads share a key.
+		if since != "" {
+			customizeQuery += fmt.Sprintf(" AND (%s, id) %s (SELECT %s, id FROM threads WHERE id = %s)", sortKey, comparisonSign, sortKey, since)
+		}
+		customizeQuery += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT %s;", sortKey, desc, desc, limit)
 	}
-	customizeQuery += fmt.Sprintf(" ORDER BY created %s LIMIT %s;", desc, limit)
 
-	rows, err := pfr.Conn.Query(customizeQuery, slug)
+	rows, err := pfr.Conn.Query(customizeQuery, values...)
 	if err != nil {
 		return []models.Thread{}, err
 	}
 	for rows.Next() {
 		var curThread models.Thread
-		err := rows.Scan(
-			&curThread.Id,
-			&curThread.Title,
-			&curThread.Author,
-			&curThread.Forum,
-			&curThread.Message,
-			&curThread.Votes,
-			&curThread.Slug,
-			&curThread.Created,
-		)
+		err := rows.Scan(threadFields(&curThread)...)
+		if err != nil {
+			return []models.Thread{}, err
+		}
+		findedThreads = append(findedThreads, curThread)
+	}
+	return findedThreads, nil
+}
+
+// CountPinnedThreads returns the number of threads pinned in the forum and the
+// number of announcements.
+func (pfr *PostgreForumRepo) CountPinnedThreads(slug string) (int, int, error) {
+	var pinned, announcements int
+	err := pfr.Conn.QueryRow(CountPinnedThreadsQuery, slug).Scan(&pinned, &announcements)
+	if err != nil {
+		return 0, 0, err
+	}
+	return pinned, announcements, nil
+}
+
+func (pfr *PostgreForumRepo) FindPinnedThreads(slug string, withSubforums bool) ([]models.Thread, error) {
+	findedThreads := make([]models.Thread, 0)
+	sqlQuery := FindPinnedThreadsQuery
+	if withSubforums {
+		sqlQuery = FindPinnedThreadsTreeQuery
+	}
+	rows, err := pfr.Conn.Query(sqlQuery, slug)
+	if err != nil {
+		return []models.Thread{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curThread models.Thread
+		err := rows.Scan(threadFields(&curThread)...)
 		if err != nil {
 			return []models.Thread{}, err
 		}
@@ -238,59 +566,349 @@ func (pfr *PostgreForumRepo) FindThreadsBySlugWithParams(slug string, limit stri
 	return findedThreads, nil
 }
 
+func (pfr *PostgreForumRepo) PinThread(threadId int64, pinData models.ThreadPin) (models.Thread, error) {
+	var pinnedThread models.Thread
+	err := pfr.Conn.QueryRow(
+		PinThreadQuery,
+		threadId,
+		pinData.Pinned,
+		pinData.Order,
+		pinData.Announcement,
+	).Scan(threadFields(&pinnedThread)...)
+	if err != nil {
+		return models.Thread{}, err
+	}
+	return pinnedThread, nil
+}
+
+func (pfr *PostgreForumRepo) GetForumTags(slug string, limit int) ([]models.TagCount, error) {
+	findedTags := make([]models.TagCount, 0)
+	rows, err := pfr.Conn.Query(GetForumTagsQuery, slug, limit)
+	if err != nil {
+		return []models.TagCount{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curTag models.TagCount
+		err := rows.Scan(&curTag.Tag, &curTag.Count)
+		if err != nil {
+			return []models.TagCount{}, err
+		}
+		findedTags = append(findedTags, curTag)
+	}
+	return findedTags, nil
+}
+
+func (pfr *PostgreForumRepo) Search(filter models.SearchFilter) ([]models.SearchResult, error) {
+	findedResults := make([]models.SearchResult, 0)
+
+	// the filters are applied to the union, postgres pushes them down into
+	// both branches
+	sqlQuery := SearchMatchesQuery
+	values := []interface{}{filter.Query}
+	if filter.Type != "" {
+		values = append(values, filter.Type)
+		sqlQuery += fmt.Sprintf(" AND kind = $%d", len(values))
+	}
+	if filter.Forum != "" {
+		values = append(values, filter.Forum)
+		sqlQuery += fmt.Sprintf(" AND forum = $%d", len(values))
+	}
+	if filter.Author != "" {
+		values = append(values, filter.Author)
+		sqlQuery += fmt.Sprintf(" AND author = $%d", len(values))
+	}
+	if filter.Thread != 0 {
+		values = append(values, filter.Thread)
+		sqlQuery += fmt.Sprintf(" AND thread = $%d", len(values))
+	}
+	if !filter.Since.IsZero() {
+		values = append(values, filter.Since)
+		sqlQuery += fmt.Sprintf(" AND created >= $%d", len(values))
+	}
+	if !filter.Until.IsZero() {
+		values = append(values, filter.Until)
+		sqlQuery += fmt.Sprintf(" AND created <= $%d", len(values))
+	}
+	if filter.AnonymousOnly {
+		sqlQuery += " AND forum NOT IN (" + SearchPrivateForumsQuery + ")"
+	}
+	sqlQuery = SearchStartQuery + sqlQuery + fmt.Sprintf(" ORDER BY rank DESC, created DESC, id LIMIT %d OFFSET %d", filter.Limit, filter.Offset) + SearchEndQuery
+
+	rows, err := pfr.Conn.Query(sqlQuery, values...)
+	if err != nil {
+		return []models.SearchResult{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curResult models.SearchResult
+		err := rows.Scan(
+			&curResult.Type,
+			&curResult.Id,
+			&curResult.Thread,
+			&curResult.Forum,
+			&curResult.Author,
+			&curResult.Title,
+			&curResult.Snippet,
+			&curResult.Rank,
+			&curResult.Created,
+		)
+		if err != nil {
+			return []models.SearchResult{}, err
+		}
+		findedResults = append(findedResults, curResult)
+	}
+	return findedResults, nil
+}
+
 func (pfr *PostgreForumRepo) FindThreadBySlugOrId(id int64, slug string) (models.Thread, error) {
 	var findedThread models.Thread
 	err := pfr.Conn.QueryRow(
 		FindThreadBySlugOrIdQuery,
 		id,
 		slug,
-	).Scan(
-		&findedThread.Id,
-		&findedThread.Title,
-		&findedThread.Author,
-		&findedThread.Forum,
-		&findedThread.Message,
-		&findedThread.Votes,
-		&findedThread.Slug,
-		&findedThread.Created,
-	)
+	).Scan(threadFields(&findedThread)...)
 	if err != nil {
 		return models.Thread{}, err
 	}
 	return findedThread, nil
 }
 
-func (pfr *PostgreForumRepo) CreatePosts(posts []models.Post, thread models.Thread) ([]models.Post, error) {
-	createdPosts := make([]models.Post, 0)
+func (pfr *PostgreForumRepo) MoveThread(thread models.Thread, from models.Forum, to models.Forum, redirect bool) (models.Thread, error) {
+	tx, err := pfr.Conn.Begin()
+	if err != nil {
+		return models.Thread{}, err
+	}
+	defer tx.Rollback()
 
-	if len(posts) == 0 {
-		return []models.Post{}, nil
+	var movedThread models.Thread
+	err = tx.QueryRow(MoveThreadQuery, thread.Id, to.Slug).Scan(threadFields(&movedThread)...)
+	if err != nil {
+		return models.Thread{}, err
 	}
 
-	createdTime := time.Now()
+	commandTag, err := tx.Exec(MoveThreadPostsQuery, thread.Id, to.Slug)
+	if err != nil {
+		return models.Thread{}, err
+	}
+	movedPosts := commandTag.RowsAffected()
 
-	sqlQuery := CreateThreadStartQuery
+	// A redirect stub takes the place of the moved thread in the old forum.
+	oldForumThreads := -1
+	if redirect {
+		oldForumThreads = 0
+	}
+	_, err = tx.Exec(UpdateForumsCountersQuery, from.Slug, oldForumThreads, -movedPosts)
+	if err != nil {
+		return models.Thread{}, err
+	}
+	_, err = tx.Exec(UpdateForumsCountersQuery, to.Slug, 1, movedPosts)
+	if err != nil {
+		return models.Thread{}, err
+	}
 
-	var values []interface{}
-	paramNumber := 1
-	var sb strings.Builder
-	for _, post := range posts {
-		_, err := pfr.FindUserByNickname(post.Author)
+	// The stub goes in first, so the users it leaves behind are removed too.
+	if redirect {
+		_, err = tx.Exec(CreateRedirectThreadQuery, thread.Title, thread.Author, from.Slug, thread.Message, thread.Created, thread.Id)
 		if err != nil {
-			return []models.Post{}, errors.New("404")
+			return models.Thread{}, err
 		}
+	}
 
-		if post.Parent == 0 {
-			sb.WriteString("(nextval('posts_id_seq'::regclass)")
-			sb.WriteString(", $")
-			sb.WriteString(strconv.Itoa(paramNumber))
-			sb.WriteString(", ARRAY[currval(pg_get_serial_sequence('posts', 'id'))::bigint], $")
-			sb.WriteString(strconv.Itoa(paramNumber + 1))
-			sb.WriteString(", $")
-			sb.WriteString(strconv.Itoa(paramNumber + 2))
-			sb.WriteString(", $")
-			sb.WriteString(strconv.Itoa(paramNumber + 3))
-			sb.WriteString(", $")
+	_, err = tx.Exec(AddThreadForumUsersQuery, thread.Id, to.Id)
+	if err != nil {
+		return models.Thread{}, err
+	}
+	_, err = tx.Exec(RemoveThreadForumUsersQuery, thread.Id, from.Id, from.Slug)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	_, err = tx.Exec(MoveThreadReputationQuery, thread.Id, from.Id, to.Id)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Thread{}, err
+	}
+	return movedThread, nil
+}
+
+func (pfr *PostgreForumRepo) MergeThreads(source models.Thread, target models.Thread, sourceForum models.Forum, targetForum models.Forum) (models.Thread, error) {
+	tx, err := pfr.Conn.Begin()
+	if err != nil {
+		return models.Thread{}, err
+	}
+	defer tx.Rollback()
+
+	// The opening message of the source thread becomes a root post of the
+	// target, and every source post is re-rooted under it so that the
+	// path-based tree orderings keep the merged conversation together.
+	var rootPost models.Post
+	err = tx.QueryRow(CreateMergeRootPostQuery, source.Author, source.Message, target.Forum, target.Id, source.Created).Scan(
+		&rootPost.Id,
+		&rootPost.Parent,
+		&rootPost.Author,
+		&rootPost.Message,
+		&rootPost.IsEdited,
+		&rootPost.Forum,
+		&rootPost.Thread,
+		&rootPost.Created,
+	)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	commandTag, err := tx.Exec(MergeThreadPostsQuery, source.Id, target.Id, target.Forum, rootPost.Id)
+	if err != nil {
+		return models.Thread{}, err
+	}
+	mergedPosts := commandTag.RowsAffected()
+
+	_, err = tx.Exec(DeleteThreadVotesQuery, source.Id)
+	if err != nil {
+		return models.Thread{}, err
+	}
+	_, err = tx.Exec(CloseMergedThreadQuery, source.Id, target.Id)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	_, err = tx.Exec(UpdateForumsCountersQuery, sourceForum.Slug, 0, -mergedPosts)
+	if err != nil {
+		return models.Thread{}, err
+	}
+	_, err = tx.Exec(UpdateForumsCountersQuery, targetForum.Slug, 0, mergedPosts+1)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	if sourceForum.Id != targetForum.Id {
+		_, err = tx.Exec(AddThreadForumUsersQuery, target.Id, targetForum.Id)
+		if err != nil {
+			return models.Thread{}, err
+		}
+		_, err = tx.Exec(RemoveThreadForumUsersQuery, target.Id, sourceForum.Id, sourceForum.Slug)
+		if err != nil {
+			return models.Thread{}, err
+		}
+	}
+
+	var mergedThread models.Thread
+	err = tx.QueryRow(RefreshThreadPostsStatsQuery, target.Id).Scan(threadFields(&mergedThread)...)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	// the moved posts already had their events, only the root post is new
+	err = addPostsCreatedEvents(tx, targetForum.Id, mergedThread, []models.Post{rootPost})
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Thread{}, err
+	}
+	return mergedThread, nil
+}
+
+func (pfr *PostgreForumRepo) SplitThread(post models.Post, threadData models.Thread) (models.Thread, error) {
+	tx, err := pfr.Conn.Begin()
+	if err != nil {
+		return models.Thread{}, err
+	}
+	defer tx.Rollback()
+
+	var createdThread models.Thread
+	err = tx.QueryRow(
+		CreateThreadQuery,
+		threadData.Title,
+		post.Author,
+		post.Forum,
+		post.Message,
+		threadData.Slug,
+		post.Created,
+		[]string{},
+	).Scan(threadFields(&createdThread)...)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	// The split post opens the new thread and stays its root post with its
+	// votes, reactions and mentions; the ancestors it had in the old thread
+	// are cut off the paths of its subtree.
+	_, err = tx.Exec(SplitPostTreeQuery, post.Thread, createdThread.Id, post.Id)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	var forumId int64
+	err = tx.QueryRow(UpdateForumsThreadCountQuery, post.Forum).Scan(&forumId)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	_, err = tx.Exec(RefreshThreadPostsStatsQuery, post.Thread)
+	if err != nil {
+		return models.Thread{}, err
+	}
+	err = tx.QueryRow(RefreshThreadPostsStatsQuery, createdThread.Id).Scan(threadFields(&createdThread)...)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	err = addThreadCreatedEvents(tx, forumId, createdThread)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Thread{}, err
+	}
+	return createdThread, nil
+}
+
+// CreatePosts inserts the posts with the nicknames mentioned in them,
+// notifies the users they concern, subscribes their authors to the thread and
+// queues them for the webhooks of the forum.
+// Mentions holds the nicknames of each post in the same order.
+func (pfr *PostgreForumRepo) CreatePosts(posts []models.Post, thread models.Thread, mentions [][]string) ([]models.Post, error) {
+	createdPosts := make([]models.Post, 0)
+
+	if len(posts) == 0 {
+		return []models.Post{}, nil
+	}
+
+	createdTime := time.Now()
+
+	sqlQuery := CreateThreadStartQuery
+
+	var values []interface{}
+	paramNumber := 1
+	var sb strings.Builder
+	for _, post := range posts {
+		_, err := pfr.FindUserByNickname(post.Author)
+		if err != nil {
+			return []models.Post{}, errors.New("404")
+		}
+
+		if post.Parent == 0 {
+			sb.WriteString("(nextval('posts_id_seq'::regclass)")
+			sb.WriteString(", $")
+			sb.WriteString(strconv.Itoa(paramNumber))
+			sb.WriteString(", ARRAY[currval(pg_get_serial_sequence('posts', 'id'))::bigint], $")
+			sb.WriteString(strconv.Itoa(paramNumber + 1))
+			sb.WriteString(", $")
+			sb.WriteString(strconv.Itoa(paramNumber + 2))
+			sb.WriteString(", $")
+			sb.WriteString(strconv.Itoa(paramNumber + 3))
+			sb.WriteString(", $")
 			sb.WriteString(strconv.Itoa(paramNumber + 4))
 			sb.WriteString(", $")
 			sb.WriteString(strconv.Itoa(paramNumber + 5))
@@ -333,175 +951,1102 @@ func (pfr *PostgreForumRepo) CreatePosts(posts []models.Post, thread models.Thre
 		}
 	}
 
-	sqlQuery = strings.TrimSuffix(sqlQuery, ",")
-	sqlQuery += " RETURNING id, parent, author, message, isEdited, forum, thread, created;"
+	sqlQuery = strings.TrimSuffix(sqlQuery, ",")
+	sqlQuery += " RETURNING id, parent, author, message, isEdited, forum, thread, created;"
+
+	tx, err := pfr.Conn.Begin()
+	if err != nil {
+		return []models.Post{}, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(sqlQuery, values...)
+	if err != nil {
+		return []models.Post{}, err
+	}
+
+	for rows.Next() {
+		var curPost models.Post
+		err := rows.Scan(
+			&curPost.Id,
+			&curPost.Parent,
+			&curPost.Author,
+			&curPost.Message,
+			&curPost.IsEdited,
+			&curPost.Forum,
+			&curPost.Thread,
+			&curPost.Created,
+		)
+
+		if err != nil {
+			rows.Close()
+			return []models.Post{}, err
+		}
+
+		createdPosts = append(createdPosts, curPost)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return []models.Post{}, rows.Err()
+	}
+
+	var forumId int64
+	err = tx.QueryRow(UpdateForumsActivityQuery, len(createdPosts), thread.Forum, createdTime).Scan(&forumId)
+	if err != nil {
+		return []models.Post{}, err
+	}
+
+	lastPost := createdPosts[0]
+	for _, post := range createdPosts {
+		if post.Id > lastPost.Id {
+			lastPost = post
+		}
+	}
+	_, err = tx.Exec(UpdateThreadPostsCountQuery, len(createdPosts), lastPost.Id, lastPost.Author, lastPost.Created, thread.Id)
+	if err != nil {
+		return []models.Post{}, err
+	}
+
+	// RETURNING yields the posts in the order of VALUES, which the paths
+	// built from currval above rely on as well
+	var mentionPostIds []int64
+	var mentionNicknames []string
+	for i, post := range createdPosts {
+		if i >= len(mentions) {
+			break
+		}
+		for _, nickname := range mentions[i] {
+			mentionPostIds = append(mentionPostIds, post.Id)
+			mentionNicknames = append(mentionNicknames, nickname)
+		}
+	}
+	if len(mentionPostIds) > 0 {
+		_, err = tx.Exec(AddPostsMentionsQuery, mentionPostIds, mentionNicknames)
+		if err != nil {
+			return []models.Post{}, err
+		}
+	}
+
+	postIds := make([]int64, 0, len(createdPosts))
+	authors := make([]string, 0, len(createdPosts))
+	for _, post := range createdPosts {
+		postIds = append(postIds, post.Id)
+		authors = append(authors, post.Author)
+	}
+	_, err = tx.Exec(AddPostsNotificationsQuery, postIds)
+	if err != nil {
+		return []models.Post{}, err
+	}
+
+	// authors watch the threads they post in
+	_, err = tx.Exec(SubscribeThreadQuery, authors, thread.Id)
+	if err != nil {
+		return []models.Post{}, err
+	}
+
+	err = addPostsCreatedEvents(tx, forumId, thread, createdPosts)
+	if err != nil {
+		return []models.Post{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return []models.Post{}, err
+	}
+	return createdPosts, nil
+}
+
+// VoteThread saves the voice of the user, a voice of 0 retracts the vote. A
+// changed vote notifies the author of the thread in the same transaction; the
+// thread counter follows the votes table through triggers.
+func (pfr *PostgreForumRepo) VoteThread(userId int64, threadId int64, voice int32) error {
+	tx, err := pfr.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	eventType := models.OutboxVoteCast
+	if voice == 0 {
+		eventType = models.OutboxVoteRetracted
+		commandTag, err := tx.Exec(DeleteVoteQuery, userId, threadId)
+		if err != nil {
+			return err
+		}
+		if commandTag.RowsAffected() == 0 {
+			return nil
+		}
+	} else {
+		var voteId int64
+		err = tx.QueryRow(SaveVoteQuery, userId, threadId, voice).Scan(&voteId)
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(AddVoteNotificationQuery, userId, threadId)
+		if err != nil {
+			return err
+		}
+	}
+
+	vote := models.VoteCast{Thread: threadId, User: userId, Voice: voice}
+	err = addOutboxEvent(tx, "thread", strconv.FormatInt(threadId, 10), eventType, vote)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetPosts returns a page of the posts of the thread. A page with a start post
+// begins at that post in ascending order, in place of since; in the
+// parent_tree sort this cuts the replies before it from its root.
+func (pfr *PostgreForumRepo) GetPosts(threadId int64, limit string, since string, sort string, desc string, comparisonSign string, startId int64) ([]models.Post, error) {
+	findedPosts := make([]models.Post, 0)
+	sqlQuery := GetPostsStartQuery
+	if startId != 0 {
+		since = strconv.FormatInt(startId, 10)
+		desc = ""
+		comparisonSign = ">="
+	}
+	switch sort {
+	case "flat":
+		if since != "" {
+			sqlQuery += fmt.Sprintf(" AND id %s %s", comparisonSign, since)
+		}
+		sqlQuery += fmt.Sprintf(" ORDER BY created %s, id %s LIMIT %s", desc, desc, limit)
+	case "tree":
+		if since != "" {
+			sqlQuery += fmt.Sprintf(" AND path %s (SELECT path FROM posts WHERE id = %s)", comparisonSign, since)
+		}
+		sqlQuery += fmt.Sprintf(" ORDER BY path[1] %s, path %s LIMIT %s", desc, desc, limit)
+	case "parent_tree":
+		if startId != 0 {
+			sqlQuery += fmt.Sprintf(" AND path >= (SELECT path FROM posts WHERE id = %d)", startId)
+		}
+		sqlQuery += " AND path && (SELECT ARRAY (SELECT id FROM posts WHERE thread = $1 AND parent = 0"
+		if since != "" {
+			sqlQuery += fmt.Sprintf(" AND path %s (SELECT path[1:1] FROM posts WHERE id = %s)", comparisonSign, since)
+		}
+		sqlQuery += fmt.Sprintf(" ORDER BY path[1] %s, path LIMIT %s)) ORDER BY path[1] %s, path", desc, limit, desc)
+	default:
+		sqlQuery = "error"
+	}
+
+	if sqlQuery == "error" {
+		return []models.Post{}, errors.New("undefined sort type")
+	}
+
+	rows, err := pfr.Conn.Query(sqlQuery, threadId)
+	if err != nil {
+		return []models.Post{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var curPost models.Post
+		err := rows.Scan(
+			&curPost.Id,
+			&curPost.Parent,
+			&curPost.Author,
+			&curPost.Message,
+			&curPost.IsEdited,
+			&curPost.Forum,
+			&curPost.Thread,
+			&curPost.Created,
+			&curPost.Votes,
+			&curPost.Reactions,
+		)
+		if err != nil {
+			return []models.Post{}, err
+		}
+		findedPosts = append(findedPosts, curPost)
+	}
+
+	return findedPosts, nil
+}
+
+func (pfr *PostgreForumRepo) UpdateThread(threadId int64, threadData models.Thread) (models.Thread, error) {
+	var updatedThread models.Thread
+	err := pfr.Conn.QueryRow(
+		UpdateThreadQuery,
+		threadData.Title,
+		threadData.Message,
+		threadId,
+		threadData.Tags,
+	).Scan(threadFields(&updatedThread)...)
+	if err != nil {
+		return models.Thread{}, err
+	}
+	return updatedThread, nil
+}
+
+func (pfr *PostgreForumRepo) GetForumUsers(forumId int64, limit string, since string, desc string, comparisonSign string) ([]models.User, error) {
+	findedUsers := make([]models.User, 0)
+	sqlQuery := GetForumUsersStartQuery
+	if since != "" {
+		sqlQuery += fmt.Sprintf(" AND nickname %s '%s'", comparisonSign, since)
+	}
+	sqlQuery += fmt.Sprintf(" ORDER BY nickname %s LIMIT %s", desc, limit)
+
+	rows, err := pfr.Conn.Query(sqlQuery, forumId)
+	if err != nil {
+		return []models.User{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curUser models.User
+		err := rows.Scan(&curUser.Id, &curUser.Nickname, &curUser.About, &curUser.Email, &curUser.Fullname)
+		if err != nil {
+			return []models.User{}, err
+		}
+		findedUsers = append(findedUsers, curUser)
+	}
+	return findedUsers, nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (pfr *PostgreForumRepo) SearchUsers(query string, forumId int64, limit int, offset int) ([]models.User, error) {
+	findedUsers := make([]models.User, 0)
+	values := []interface{}{query, likeEscaper.Replace(query) + "%"}
+	sqlQuery := SearchUsersStartQuery
+	if forumId != 0 {
+		sqlQuery += " AND id IN (SELECT user_id FROM forum_users WHERE forum_id = $3)"
+		values = append(values, forumId)
+	}
+	sqlQuery += SearchUsersEndQuery + fmt.Sprintf(" LIMIT %d OFFSET %d;", limit, offset)
+
+	rows, err := pfr.Conn.Query(sqlQuery, values...)
+	if err != nil {
+		return []models.User{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curUser models.User
+		err := rows.Scan(&curUser.Id, &curUser.Nickname, &curUser.About, &curUser.Email, &curUser.Fullname)
+		if err != nil {
+			return []models.User{}, err
+		}
+		findedUsers = append(findedUsers, curUser)
+	}
+	return findedUsers, nil
+}
+
+// savePostMentions replaces the mentions of the post and returns the nicknames
+// that were not mentioned in it before.
+func savePostMentions(tx *pgx.Tx, postId int64, nicknames []string) ([]string, error) {
+	addedNicknames := make([]string, 0)
+	if nicknames == nil {
+		nicknames = []string{}
+	}
+
+	_, err := tx.Exec(DeletePostMentionsQuery, postId, nicknames)
+	if err != nil {
+		return []string{}, err
+	}
+	if len(nicknames) > 0 {
+		rows, err := tx.Query(AddPostMentionsQuery, postId, nicknames)
+		if err != nil {
+			return []string{}, err
+		}
+		for rows.Next() {
+			var nickname string
+			err := rows.Scan(&nickname)
+			if err != nil {
+				rows.Close()
+				return []string{}, err
+			}
+			addedNicknames = append(addedNicknames, nickname)
+		}
+		rows.Close()
+		if rows.Err() != nil {
+			return []string{}, rows.Err()
+		}
+	}
+
+	return addedNicknames, nil
+}
+
+func (pfr *PostgreForumRepo) GetUserMentions(userId int64, limit string, since string, desc string, comparisonSign string, anonymousOnly bool) ([]models.Post, error) {
+	findedPosts := make([]models.Post, 0)
+	values := []interface{}{userId}
+	sqlQuery := GetUserMentionsStartQuery
+	if since != "" {
+		sqlQuery += fmt.Sprintf(" AND p.id %s $2", comparisonSign)
+		values = append(values, since)
+	}
+	if anonymousOnly {
+		sqlQuery += " AND p.forum NOT IN (" + SearchPrivateForumsQuery + ")"
+	}
+	sqlQuery += fmt.Sprintf(" ORDER BY p.id %s LIMIT %s;", desc, limit)
+
+	rows, err := pfr.Conn.Query(sqlQuery, values...)
+	if err != nil {
+		return []models.Post{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curPost models.Post
+		err := rows.Scan(
+			&curPost.Id,
+			&curPost.Parent,
+			&curPost.Author,
+			&curPost.Message,
+			&curPost.IsEdited,
+			&curPost.Forum,
+			&curPost.Thread,
+			&curPost.Created,
+		)
+		if err != nil {
+			return []models.Post{}, err
+		}
+		findedPosts = append(findedPosts, curPost)
+	}
+	return findedPosts, nil
+}
+
+// AddNotification stores the notification for the user with the given
+// nickname unless the user has turned its type off.
+
+func (pfr *PostgreForumRepo) GetNotifications(userId int64, limit string, since string, unreadOnly bool) ([]models.Notification, error) {
+	findedNotifications := make([]models.Notification, 0)
+	values := []interface{}{userId}
+	sqlQuery := GetNotificationsStartQuery
+	if since != "" {
+		sqlQuery += " AND id < $2"
+		values = append(values, since)
+	}
+	if unreadOnly {
+		sqlQuery += " AND NOT is_read"
+	}
+	sqlQuery += fmt.Sprintf(" ORDER BY id DESC LIMIT %s;", limit)
+
+	rows, err := pfr.Conn.Query(sqlQuery, values...)
+	if err != nil {
+		return []models.Notification{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curNotification models.Notification
+		err := rows.Scan(
+			&curNotification.Id,
+			&curNotification.Type,
+			&curNotification.Actor,
+			&curNotification.Thread,
+			&curNotification.Post,
+			&curNotification.Read,
+			&curNotification.Created,
+		)
+		if err != nil {
+			return []models.Notification{}, err
+		}
+		findedNotifications = append(findedNotifications, curNotification)
+	}
+	return findedNotifications, nil
+}
+
+func (pfr *PostgreForumRepo) CountUnreadNotifications(userId int64) (int32, error) {
+	var unread int32
+	err := pfr.Conn.QueryRow(CountUnreadNotificationsQuery, userId).Scan(&unread)
+	if err != nil {
+		return 0, err
+	}
+	return unread, nil
+}
+
+func (pfr *PostgreForumRepo) MarkNotificationsRead(userId int64, ids []int64) error {
+	var err error
+	if len(ids) == 0 {
+		_, err = pfr.Conn.Exec(MarkAllNotificationsReadQuery, userId)
+	} else {
+		_, err = pfr.Conn.Exec(MarkNotificationsReadQuery, userId, ids)
+	}
+	return err
+}
+
+func (pfr *PostgreForumRepo) GetNotificationPreferences(userId int64) (models.NotificationPreferences, error) {
+	var findedPreferences models.NotificationPreferences
+	err := pfr.Conn.QueryRow(
+		GetNotificationPreferencesQuery,
+		userId,
+	).Scan(
+		&findedPreferences.Reply,
+		&findedPreferences.ThreadPost,
+		&findedPreferences.Vote,
+		&findedPreferences.Mention,
+	)
+	if err != nil {
+		return models.NotificationPreferences{}, err
+	}
+	return findedPreferences, nil
+}
+
+func (pfr *PostgreForumRepo) UpdateNotificationPreferences(userId int64, preferences models.NotificationPreferences) error {
+	_, err := pfr.Conn.Exec(
+		UpdateNotificationPreferencesQuery,
+		userId,
+		preferences.Reply,
+		preferences.ThreadPost,
+		preferences.Vote,
+		preferences.Mention,
+	)
+	return err
+}
+
+func (pfr *PostgreForumRepo) SubscribeThread(nicknames []string, threadId int64) error {
+	_, err := pfr.Conn.Exec(SubscribeThreadQuery, nicknames, threadId)
+	return err
+}
+
+func (pfr *PostgreForumRepo) UnsubscribeThread(userId int64, threadId int64) error {
+	_, err := pfr.Conn.Exec(UnsubscribeThreadQuery, userId, threadId)
+	return err
+}
+
+func (pfr *PostgreForumRepo) IsThreadSubscribed(nickname string, threadId int64) (bool, error) {
+	var subscribed bool
+	err := pfr.Conn.QueryRow(IsThreadSubscribedQuery, nickname, threadId).Scan(&subscribed)
+	if err != nil {
+		return false, err
+	}
+	return subscribed, nil
+}
+
+func (pfr *PostgreForumRepo) SubscribeForum(userId int64, forumId int64) error {
+	_, err := pfr.Conn.Exec(SubscribeForumQuery, userId, forumId)
+	return err
+}
+
+func (pfr *PostgreForumRepo) UnsubscribeForum(userId int64, forumId int64) error {
+	_, err := pfr.Conn.Exec(UnsubscribeForumQuery, userId, forumId)
+	return err
+}
+
+func (pfr *PostgreForumRepo) GetFeedCursor(userId int64) (int64, error) {
+	var lastPostId int64
+	err := pfr.Conn.QueryRow(GetFeedCursorQuery, userId).Scan(&lastPostId)
+	if err != nil {
+		return 0, err
+	}
+	return lastPostId, nil
+}
+
+func (pfr *PostgreForumRepo) UpdateFeedCursor(userId int64, lastPostId int64) error {
+	_, err := pfr.Conn.Exec(UpdateFeedCursorQuery, userId, lastPostId)
+	return err
+}
+
+func (pfr *PostgreForumRepo) GetFeed(user models.User, since int64, limit int) ([]models.Post, error) {
+	findedPosts := make([]models.Post, 0)
+	rows, err := pfr.Conn.Query(GetFeedQuery, user.Id, since, user.Nickname, limit)
+	if err != nil {
+		return []models.Post{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curPost models.Post
+		err := rows.Scan(
+			&curPost.Id,
+			&curPost.Parent,
+			&curPost.Author,
+			&curPost.Message,
+			&curPost.IsEdited,
+			&curPost.Forum,
+			&curPost.Thread,
+			&curPost.Created,
+		)
+		if err != nil {
+			return []models.Post{}, err
+		}
+		findedPosts = append(findedPosts, curPost)
+	}
+	return findedPosts, nil
+}
+
+// GetThreadRead returns the read marker of the thread and the posts read past
+// it.
+func (pfr *PostgreForumRepo) GetThreadRead(userId int64, threadId int64) (int64, []int64, error) {
+	var lastPostId int64
+	err := pfr.Conn.QueryRow(GetThreadReadQuery, userId, threadId).Scan(&lastPostId)
+	if err != nil {
+		return 0, []int64{}, err
+	}
+
+	readPostIds := make([]int64, 0)
+	rows, err := pfr.Conn.Query(GetReadPostsQuery, userId, threadId)
+	if err != nil {
+		return 0, []int64{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postId int64
+		err := rows.Scan(&postId)
+		if err != nil {
+			return 0, []int64{}, err
+		}
+		readPostIds = append(readPostIds, postId)
+	}
+	return lastPostId, readPostIds, nil
+}
+
+// MarkThreadRead moves the read marker of the thread forward, records the
+// posts read past it and returns the resulting marker, it never goes back.
+func (pfr *PostgreForumRepo) MarkThreadRead(userId int64, threadId int64, lastPostId int64, readPostIds []int64) (int64, error) {
+	if readPostIds == nil {
+		readPostIds = []int64{}
+	}
+
+	var markedPostId int64
+	err := pfr.Conn.QueryRow(MarkThreadReadQuery, userId, threadId, lastPostId, readPostIds).Scan(&markedPostId)
+	if err != nil {
+		return 0, err
+	}
+	return markedPostId, nil
+}
+
+func (pfr *PostgreForumRepo) CountUnreadPosts(userId int64, threadIds []int64) (map[int64]int32, error) {
+	unreadPosts := make(map[int64]int32)
+	rows, err := pfr.Conn.Query(CountUnreadPostsQuery, userId, threadIds)
+	if err != nil {
+		return map[int64]int32{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var threadId int64
+		var unread int32
+		err := rows.Scan(&threadId, &unread)
+		if err != nil {
+			return map[int64]int32{}, err
+		}
+		unreadPosts[threadId] = unread
+	}
+	return unreadPosts, nil
+}
+
+// FindFirstUnreadPost returns the first post the user hasn't read in the given
+// sort order, or 0 when everything is read.
+func (pfr *PostgreForumRepo) FindFirstUnreadPost(userId int64, threadId int64, lastPostId int64, sort string) (int64, error) {
+	sqlQuery := FindFirstUnreadTreeQuery
+	if sort == "flat" {
+		sqlQuery = FindFirstUnreadFlatQuery
+	}
+
+	var postId int64
+	err := pfr.Conn.QueryRow(sqlQuery, threadId, lastPostId, userId).Scan(&postId)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return postId, nil
+}
+
+// FindFirstUnlistedPost returns the first post after lastPostId, in the order
+// of ids, that is not one of postIds, or 0 when there is none.
+func (pfr *PostgreForumRepo) FindFirstUnlistedPost(threadId int64, lastPostId int64, postIds []int64) (int64, error) {
+	var postId int64
+	err := pfr.Conn.QueryRow(FindFirstUnlistedQuery, threadId, lastPostId, postIds).Scan(&postId)
+	if err != nil {
+		return 0, err
+	}
+	return postId, nil
+}
+
+func (pfr *PostgreForumRepo) GetLastStreamEventId() (int64, error) {
+	var lastEventId int64
+	err := pfr.Conn.QueryRow(GetLastStreamEventIdQuery).Scan(&lastEventId)
+	if err != nil {
+		return 0, err
+	}
+	return lastEventId, nil
+}
+
+// GetStreamEvents returns the events after afterId, of all channels when
+// channel is empty.
+func (pfr *PostgreForumRepo) GetStreamEvents(channel string, afterId int64, limit int) ([]models.StreamEvent, error) {
+	findedEvents := make([]models.StreamEvent, 0)
+	values := []interface{}{afterId}
+	sqlQuery := GetStreamEventsStartQuery
+	if channel != "" {
+		sqlQuery += " AND channel = $2"
+		values = append(values, channel)
+	}
+	sqlQuery += fmt.Sprintf(" ORDER BY id LIMIT %d;", limit)
+
+	rows, err := pfr.Conn.Query(sqlQuery, values...)
+	if err != nil {
+		return []models.StreamEvent{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curEvent models.StreamEvent
+		var payload []byte
+		err := rows.Scan(&curEvent.Id, &curEvent.Channel, &curEvent.Type, &payload, &curEvent.Created)
+		if err != nil {
+			return []models.StreamEvent{}, err
+		}
+		curEvent.Payload = payload
+		findedEvents = append(findedEvents, curEvent)
+	}
+	return findedEvents, nil
+}
+
+func (pfr *PostgreForumRepo) DeleteStreamEvents(before time.Time) error {
+	_, err := pfr.Conn.Exec(DeleteStreamEventsQuery, before)
+	return err
+}
+
+// ListenStreamEvents holds a connection listening for new stream events until
+// ctx is done or the connection fails. wakeups gets a value once listening has
+// started and after every notification; pending wakeups are coalesced.
+func (pfr *PostgreForumRepo) ListenStreamEvents(ctx context.Context, wakeups chan<- struct{}) error {
+	conn, err := pfr.Conn.Acquire()
+	if err != nil {
+		return err
+	}
+	defer pfr.Conn.Release(conn)
+
+	err = conn.Listen(streamEventsChannel)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case wakeups <- struct{}{}:
+		default:
+		}
+
+		_, err = conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func webhookFields(webhook *models.Webhook) []interface{} {
+	return []interface{}{
+		&webhook.Id,
+		&webhook.Forum,
+		&webhook.Url,
+		&webhook.Secret,
+		&webhook.Events,
+		&webhook.Active,
+		&webhook.Created,
+	}
+}
+
+func (pfr *PostgreForumRepo) CreateWebhook(forumId int64, webhookData models.Webhook) (models.Webhook, error) {
+	var createdWebhook models.Webhook
+	err := pfr.Conn.QueryRow(
+		CreateWebhookQuery,
+		forumId,
+		webhookData.Url,
+		webhookData.Secret,
+		webhookData.Events,
+	).Scan(webhookFields(&createdWebhook)...)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	return createdWebhook, nil
+}
+
+func (pfr *PostgreForumRepo) FindWebhooks(forumId int64) ([]models.Webhook, error) {
+	findedWebhooks := make([]models.Webhook, 0)
+	rows, err := pfr.Conn.Query(FindWebhooksQuery, forumId)
+	if err != nil {
+		return []models.Webhook{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curWebhook models.Webhook
+		err := rows.Scan(webhookFields(&curWebhook)...)
+		if err != nil {
+			return []models.Webhook{}, err
+		}
+		findedWebhooks = append(findedWebhooks, curWebhook)
+	}
+	return findedWebhooks, nil
+}
+
+func (pfr *PostgreForumRepo) FindWebhook(id int64, forumId int64) (models.Webhook, error) {
+	var findedWebhook models.Webhook
+	err := pfr.Conn.QueryRow(FindWebhookQuery, id, forumId).Scan(webhookFields(&findedWebhook)...)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	return findedWebhook, nil
+}
+
+func (pfr *PostgreForumRepo) DeleteWebhook(id int64) error {
+	_, err := pfr.Conn.Exec(DeleteWebhookQuery, id)
+	return err
+}
+
+// enqueueWebhookDeliveries queues the events for every active webhook of the
+// forum that is interested in them, in the transaction of the change they
+// describe.
+func enqueueWebhookDeliveries(tx *pgx.Tx, forumId int64, events []models.WebhookEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	payloads := make([]string, 0, len(events))
+	for _, event := range events {
+		payload, err := event.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		payloads = append(payloads, string(payload))
+	}
+
+	_, err := tx.Exec(EnqueueWebhookDeliveriesQuery, forumId, events[0].Event, payloads)
+	return err
+}
+
+func scanWebhookDelivery(row interface{ Scan(...interface{}) error }, delivery *models.WebhookDelivery, extra ...interface{}) error {
+	var payload []byte
+	fields := []interface{}{
+		&delivery.Id,
+		&delivery.Webhook,
+		&delivery.Event,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttempt,
+		&delivery.StatusCode,
+		&delivery.Error,
+		&delivery.Created,
+		&delivery.DeliveredAt,
+	}
+	err := row.Scan(append(fields, extra...)...)
+	if err != nil {
+		return err
+	}
+	delivery.Payload = payload
+	return nil
+}
+
+func (pfr *PostgreForumRepo) GetWebhookDeliveries(webhookId int64, limit string, since string) ([]models.WebhookDelivery, error) {
+	findedDeliveries := make([]models.WebhookDelivery, 0)
+	values := []interface{}{webhookId}
+	sqlQuery := GetWebhookDeliveriesStartQuery
+	if since != "" {
+		sqlQuery += " AND d.id < $2"
+		values = append(values, since)
+	}
+	sqlQuery += fmt.Sprintf(" ORDER BY d.id DESC LIMIT %s;", limit)
+
+	rows, err := pfr.Conn.Query(sqlQuery, values...)
+	if err != nil {
+		return []models.WebhookDelivery{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curDelivery models.WebhookDelivery
+		err := scanWebhookDelivery(rows, &curDelivery)
+		if err != nil {
+			return []models.WebhookDelivery{}, err
+		}
+		findedDeliveries = append(findedDeliveries, curDelivery)
+	}
+	return findedDeliveries, nil
+}
+
+// RedeliverWebhookDelivery queues a copy of the delivery, the original one is
+// kept in the log as it is.
+func (pfr *PostgreForumRepo) RedeliverWebhookDelivery(webhookId int64, deliveryId int64) (models.WebhookDelivery, error) {
+	var createdDelivery models.WebhookDelivery
+	err := scanWebhookDelivery(pfr.Conn.QueryRow(RedeliverWebhookDeliveryQuery, deliveryId, webhookId), &createdDelivery)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	return createdDelivery, nil
+}
+
+func (pfr *PostgreForumRepo) ClaimWebhookDeliveries(limit int, lease time.Duration) ([]models.WebhookJob, error) {
+	claimedJobs := make([]models.WebhookJob, 0)
+	rows, err := pfr.Conn.Query(ClaimWebhookDeliveriesQuery, limit, int64(lease/time.Second))
+	if err != nil {
+		return []models.WebhookJob{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curJob models.WebhookJob
+		err := scanWebhookDelivery(rows, &curJob.Delivery, &curJob.Url, &curJob.Secret)
+		if err != nil {
+			return []models.WebhookJob{}, err
+		}
+		claimedJobs = append(claimedJobs, curJob)
+	}
+	return claimedJobs, nil
+}
+
+func (pfr *PostgreForumRepo) CompleteWebhookDelivery(delivery models.WebhookDelivery) error {
+	_, err := pfr.Conn.Exec(
+		CompleteWebhookDeliveryQuery,
+		delivery.Id,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttempt,
+		delivery.StatusCode,
+		delivery.Error,
+		delivery.DeliveredAt,
+	)
+	return err
+}
+
+func (pfr *PostgreForumRepo) GetUserReputation(userId int64) ([]models.ForumReputation, error) {
+	findedReputation := make([]models.ForumReputation, 0)
+	rows, err := pfr.Conn.Query(GetUserReputationQuery, userId)
+	if err != nil {
+		return []models.ForumReputation{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curReputation models.ForumReputation
+		err := rows.Scan(&curReputation.Forum, &curReputation.Reputation)
+		if err != nil {
+			return []models.ForumReputation{}, err
+		}
+		findedReputation = append(findedReputation, curReputation)
+	}
+	return findedReputation, nil
+}
+
+func (pfr *PostgreForumRepo) CountThreadVotes(threadId int64) (int32, int32, error) {
+	var up, down int32
+	err := pfr.Conn.QueryRow(CountThreadVotesQuery, threadId).Scan(&up, &down)
+	if err != nil {
+		return 0, 0, err
+	}
+	return up, down, nil
+}
+
+func (pfr *PostgreForumRepo) GetThreadVoters(threadId int64, limit string, since string, desc string, comparisonSign string) ([]models.Voter, error) {
+	findedVoters := make([]models.Voter, 0)
+	values := []interface{}{threadId}
+	sqlQuery := GetThreadVotersStartQuery
+	if since != "" {
+		sqlQuery += fmt.Sprintf(" AND v.id %s $2", comparisonSign)
+		values = append(values, since)
+	}
+	sqlQuery += fmt.Sprintf(" ORDER BY v.id %s LIMIT %s;", desc, limit)
+
+	rows, err := pfr.Conn.Query(sqlQuery, values...)
+	if err != nil {
+		return []models.Voter{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curVoter models.Voter
+		err := rows.Scan(&curVoter.Id, &curVoter.Nickname, &curVoter.Voice, &curVoter.Created)
+		if err != nil {
+			return []models.Voter{}, err
+		}
+		findedVoters = append(findedVoters, curVoter)
+	}
+	return findedVoters, nil
+}
+
+func (pfr *PostgreForumRepo) GetUserVotes(userId int64, limit string, since string, desc string, comparisonSign string) ([]models.UserVote, error) {
+	findedVotes := make([]models.UserVote, 0)
+	values := []interface{}{userId}
+	sqlQuery := GetUserVotesStartQuery
+	if since != "" {
+		sqlQuery += fmt.Sprintf(" AND v.id %s $2", comparisonSign)
+		values = append(values, since)
+	}
+	sqlQuery += fmt.Sprintf(" ORDER BY v.id %s LIMIT %s;", desc, limit)
 
 	rows, err := pfr.Conn.Query(sqlQuery, values...)
 	if err != nil {
-		return []models.Post{}, err
+		return []models.UserVote{}, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var curPost models.Post
+		var curVote models.UserVote
 		err := rows.Scan(
-			&curPost.Id,
-			&curPost.Parent,
-			&curPost.Author,
-			&curPost.Message,
-			&curPost.IsEdited,
-			&curPost.Forum,
-			&curPost.Thread,
-			&curPost.Created,
+			&curVote.Id,
+			&curVote.Thread,
+			&curVote.Slug,
+			&curVote.Title,
+			&curVote.Forum,
+			&curVote.Voice,
+			&curVote.Created,
 		)
-
 		if err != nil {
-			return []models.Post{}, err
+			return []models.UserVote{}, err
 		}
-
-		createdPosts = append(createdPosts, curPost)
+		findedVotes = append(findedVotes, curVote)
 	}
+	return findedVotes, nil
+}
 
-	var forumId int64
-	err = pfr.Conn.QueryRow(UpdateForumsPostsCountQuery, len(createdPosts), thread.Forum).Scan(&forumId)
+// VotePost saves the voice of the user, a voice of 0 retracts the vote. The
+// post counter follows the votes through triggers, as for threads.
+func (pfr *PostgreForumRepo) VotePost(userId int64, postId int64, voice int32) error {
+	tx, err := pfr.Conn.Begin()
 	if err != nil {
-		return []models.Post{}, err
+		return err
 	}
+	defer tx.Rollback()
 
+	eventType := models.OutboxPostVoteCast
+	var commandTag pgx.CommandTag
+	if voice == 0 {
+		eventType = models.OutboxPostVoteRetracted
+		commandTag, err = tx.Exec(DeletePostVoteQuery, userId, postId)
+	} else {
+		commandTag, err = tx.Exec(SavePostVoteQuery, userId, postId, voice)
+	}
 	if err != nil {
-		return []models.Post{}, err
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return nil
 	}
 
-	return createdPosts, nil
-}
-
-func (pfr *PostgreForumRepo) FindVote(userId int64, threadId int64) error {
-	var findedVoteId int64
-	err := pfr.Conn.QueryRow(FindVoteQuery, userId, threadId).Scan(&findedVoteId)
+	vote := models.PostVoteCast{Post: postId, User: userId, Voice: voice}
+	err = addOutboxEvent(tx, "post", strconv.FormatInt(postId, 10), eventType, vote)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	return tx.Commit()
 }
 
-func (pfr *PostgreForumRepo) UpdateVoteThread(userId int64, threadId int64, voice int32) error {
-	var updatedVoteId int64
-	err := pfr.Conn.QueryRow(UpdateVoteQuery, userId, threadId, voice).Scan(&updatedVoteId)
+func (pfr *PostgreForumRepo) AddPostReaction(userId int64, postId int64, emoji string) error {
+	return pfr.reactToPost(AddPostReactionQuery, models.OutboxPostReactionAdded, userId, postId, emoji)
+}
+
+func (pfr *PostgreForumRepo) DeletePostReaction(userId int64, postId int64, emoji string) error {
+	return pfr.reactToPost(DeletePostReactionQuery, models.OutboxPostReactionRemoved, userId, postId, emoji)
+}
+
+// reactToPost adds or removes the reaction with its outbox event, a reaction
+// that is already there or already gone records none. The post counters
+// follow the reactions through triggers, as for votes.
+func (pfr *PostgreForumRepo) reactToPost(sqlQuery string, eventType string, userId int64, postId int64, emoji string) error {
+	tx, err := pfr.Conn.Begin()
 	if err != nil {
 		return err
 	}
-	return nil
-}
+	defer tx.Rollback()
 
-func (pfr *PostgreForumRepo) AddVoteThread(userId int64, threadId int64, voice int32) error {
-	var updatedVoteId int64
-	err := pfr.Conn.QueryRow(AddVoteQuery, userId, threadId, voice).Scan(&updatedVoteId)
+	commandTag, err := tx.Exec(sqlQuery, userId, postId, emoji)
 	if err != nil {
 		return err
 	}
-	return nil
-}
-
-func (pfr *PostgreForumRepo) GetPosts(threadId int64, limit string, since string, sort string, desc string, comparisonSign string) ([]models.Post, error) {
-	findedPosts := make([]models.Post, 0)
-	sqlQuery := GetPostsStartQuery
-	switch sort {
-	case "flat":
-		if since != "" {
-			sqlQuery += fmt.Sprintf(" AND id %s %s", comparisonSign, since)
-		}
-		sqlQuery += fmt.Sprintf(" ORDER BY created %s, id %s LIMIT %s", desc, desc, limit)
-	case "tree":
-		if since != "" {
-			sqlQuery += fmt.Sprintf(" AND path %s (SELECT path FROM posts WHERE id = %s)", comparisonSign, since)
-		}
-		sqlQuery += fmt.Sprintf(" ORDER BY path[1] %s, path %s LIMIT %s", desc, desc, limit)
-	case "parent_tree":
-		sqlQuery += " AND path && (SELECT ARRAY (SELECT id FROM posts WHERE thread = $1 AND parent = 0"
-		if since != "" {
-			sqlQuery += fmt.Sprintf(" AND path %s (SELECT path[1:1] FROM posts WHERE id = %s)", comparisonSign, since)
-		}
-		sqlQuery += fmt.Sprintf(" ORDER BY path[1] %s, path LIMIT %s)) ORDER BY path[1] %s, path", desc, limit, desc)
-	default:
-		sqlQuery = "error"
+	if commandTag.RowsAffected() == 0 {
+		return nil
 	}
 
-	if sqlQuery == "error" {
-		return []models.Post{}, errors.New("undefined sort type")
+	reaction := models.PostReaction{Post: postId, User: userId, Emoji: emoji}
+	err = addOutboxEvent(tx, "post", strconv.FormatInt(postId, 10), eventType, reaction)
+	if err != nil {
+		return err
 	}
 
-	rows, err := pfr.Conn.Query(sqlQuery, threadId)
+	return tx.Commit()
+}
+
+func (pfr *PostgreForumRepo) GetPostReactions(postId int64) ([]models.ReactionGroup, error) {
+	findedReactions := make([]models.ReactionGroup, 0)
+	rows, err := pfr.Conn.Query(GetPostReactionsQuery, postId)
 	if err != nil {
-		return []models.Post{}, err
+		return []models.ReactionGroup{}, err
 	}
 	defer rows.Close()
+
 	for rows.Next() {
-		var curPost models.Post
-		err := rows.Scan(
-			&curPost.Id,
-			&curPost.Parent,
-			&curPost.Author,
-			&curPost.Message,
-			&curPost.IsEdited,
-			&curPost.Forum,
-			&curPost.Thread,
-			&curPost.Created,
-		)
+		var curReaction models.ReactionGroup
+		err := rows.Scan(&curReaction.Emoji, &curReaction.Count, &curReaction.Users)
 		if err != nil {
-			return []models.Post{}, err
+			return []models.ReactionGroup{}, err
 		}
-		findedPosts = append(findedPosts, curPost)
+		findedReactions = append(findedReactions, curReaction)
 	}
-
-	return findedPosts, nil
+	return findedReactions, nil
 }
 
-func (pfr *PostgreForumRepo) UpdateThread(threadId int64, threadData models.Thread) (models.Thread, error) {
-	var updatedThread models.Thread
-	err := pfr.Conn.QueryRow(
-		UpdateThreadQuery,
-		threadData.Title,
-		threadData.Message,
-		threadId,
-	).Scan(
-		&updatedThread.Id,
-		&updatedThread.Title,
-		&updatedThread.Author,
-		&updatedThread.Forum,
-		&updatedThread.Message,
-		&updatedThread.Votes,
-		&updatedThread.Slug,
-		&updatedThread.Created,
-	)
+// addOutboxEvent stores the event in the transaction of the change, so it is
+// only published if the change is committed.
+func addOutboxEvent(tx *pgx.Tx, aggregate string, aggregateId string, eventType string, payload easyjson.Marshaler) error {
+	data, err := easyjson.Marshal(payload)
 	if err != nil {
-		return models.Thread{}, err
+		return err
 	}
-	return updatedThread, nil
+	_, err = tx.Exec(AddOutboxEventQuery, aggregate, aggregateId, eventType, string(data))
+	return err
 }
 
-func (pfr *PostgreForumRepo) GetForumUsers(forumId int64, limit string, since string, desc string, comparisonSign string) ([]models.User, error) {
-	findedUsers := make([]models.User, 0)
-	sqlQuery := GetForumUsersStartQuery
-	if since != "" {
-		sqlQuery += fmt.Sprintf(" AND nickname %s '%s'", comparisonSign, since)
-	}
-	sqlQuery += fmt.Sprintf(" ORDER BY nickname %s LIMIT %s", desc, limit)
-
-	rows, err := pfr.Conn.Query(sqlQuery, forumId)
+func (pfr *PostgreForumRepo) ClaimOutboxEvents(limit int, lease time.Duration) ([]models.OutboxEvent, error) {
+	claimedEvents := make([]models.OutboxEvent, 0)
+	rows, err := pfr.Conn.Query(ClaimOutboxEventsQuery, limit, int64(lease/time.Second))
 	if err != nil {
-		return []models.User{}, err
+		return []models.OutboxEvent{}, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var curUser models.User
-		err := rows.Scan(&curUser.Id, &curUser.Nickname, &curUser.About, &curUser.Email, &curUser.Fullname)
+		var curEvent models.OutboxEvent
+		var payload []byte
+		err := rows.Scan(
+			&curEvent.Id,
+			&curEvent.Aggregate,
+			&curEvent.AggregateId,
+			&curEvent.Type,
+			&payload,
+			&curEvent.Created,
+			&curEvent.Attempts,
+			&curEvent.PublishedTo,
+		)
 		if err != nil {
-			return []models.User{}, err
+			return []models.OutboxEvent{}, err
 		}
-		findedUsers = append(findedUsers, curUser)
+		curEvent.Payload = payload
+		claimedEvents = append(claimedEvents, curEvent)
 	}
-	return findedUsers, nil
+
+	// UPDATE ... RETURNING doesn't keep the order of the subquery
+	sort.Slice(claimedEvents, func(i, j int) bool {
+		return claimedEvents[i].Id < claimedEvents[j].Id
+	})
+	return claimedEvents, nil
+}
+
+func (pfr *PostgreForumRepo) MarkOutboxEventPublished(id int64, publishedTo []string) error {
+	_, err := pfr.Conn.Exec(PublishOutboxEventQuery, id, publishedTo)
+	return err
+}
+
+func (pfr *PostgreForumRepo) RetryOutboxEvent(id int64, publishedTo []string, retryAt time.Time, reason string) error {
+	_, err := pfr.Conn.Exec(RetryOutboxEventQuery, id, publishedTo, retryAt, reason)
+	return err
+}
+
+func (pfr *PostgreForumRepo) DeleteOutboxEvents(before time.Time) error {
+	_, err := pfr.Conn.Exec(DeleteOutboxEventsQuery, before)
+	return err
 }
 
 func (pfr *PostgreForumRepo) GetPostInfo(postId int64, withUser bool, withForum bool, withThread bool) (models.PostFull, error) {
@@ -519,6 +2064,8 @@ func (pfr *PostgreForumRepo) GetPostInfo(postId int64, withUser bool, withForum
 		&findedPost.Forum,
 		&findedPost.Thread,
 		&findedPost.Created,
+		&findedPost.Votes,
+		&findedPost.Reactions,
 	)
 	if err != nil {
 		return models.PostFull{}, err
@@ -537,6 +2084,8 @@ func (pfr *PostgreForumRepo) GetPostInfo(postId int64, withUser bool, withForum
 			&findedUser.About,
 			&findedUser.Email,
 			&findedUser.Fullname,
+			&findedUser.IsAdmin,
+			&findedUser.Created,
 		)
 		if err != nil {
 			return models.PostFull{}, err
@@ -550,14 +2099,7 @@ func (pfr *PostgreForumRepo) GetPostInfo(postId int64, withUser bool, withForum
 
 			FindForumBySlugQuery,
 			findedPost.Forum,
-		).Scan(
-			&findedForum.Id,
-			&findedForum.Title,
-			&findedForum.User,
-			&findedForum.Slug,
-			&findedForum.Posts,
-			&findedForum.Threads,
-		)
+		).Scan(forumFields(&findedForum)...)
 		if err != nil {
 			return models.PostFull{}, err
 		}
@@ -570,16 +2112,7 @@ func (pfr *PostgreForumRepo) GetPostInfo(postId int64, withUser bool, withForum
 
 			FindThreadByIdQuery,
 			findedPost.Thread,
-		).Scan(
-			&findedThread.Id,
-			&findedThread.Title,
-			&findedThread.Author,
-			&findedThread.Forum,
-			&findedThread.Message,
-			&findedThread.Votes,
-			&findedThread.Slug,
-			&findedThread.Created,
-		)
+		).Scan(threadFields(&findedThread)...)
 		if err != nil {
 			return models.PostFull{}, err
 		}
@@ -603,6 +2136,8 @@ func (pfr *PostgreForumRepo) FindPost(postId int64) (models.Post, error) {
 		&findedPost.Forum,
 		&findedPost.Thread,
 		&findedPost.Created,
+		&findedPost.Votes,
+		&findedPost.Reactions,
 	)
 	if err != nil {
 		return models.Post{}, err
@@ -610,9 +2145,17 @@ func (pfr *PostgreForumRepo) FindPost(postId int64) (models.Post, error) {
 	return findedPost, nil
 }
 
-func (pfr *PostgreForumRepo) UpdatePost(postData models.Post) (models.Post, error) {
+// UpdatePost saves the post with the nicknames now mentioned in it and
+// notifies the users that were not mentioned before.
+func (pfr *PostgreForumRepo) UpdatePost(postData models.Post, mentions []string) (models.Post, error) {
+	tx, err := pfr.Conn.Begin()
+	if err != nil {
+		return models.Post{}, err
+	}
+	defer tx.Rollback()
+
 	var updatedPost models.Post
-	err := pfr.Conn.QueryRow(
+	err = tx.QueryRow(
 		UpdatePostQuery,
 		postData.Id,
 		postData.Parent,
@@ -635,9 +2178,216 @@ func (pfr *PostgreForumRepo) UpdatePost(postData models.Post) (models.Post, erro
 	if err != nil {
 		return models.Post{}, err
 	}
+
+	addedNicknames, err := savePostMentions(tx, updatedPost.Id, mentions)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if len(addedNicknames) > 0 {
+		_, err = tx.Exec(AddMentionNotificationsQuery, addedNicknames, updatedPost.Author, updatedPost.Thread, updatedPost.Id)
+		if err != nil {
+			return models.Post{}, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Post{}, err
+	}
 	return updatedPost, nil
 }
 
+func (pfr *PostgreForumRepo) DeletePost(postData models.Post) (models.Thread, error) {
+	tx, err := pfr.Conn.Begin()
+	if err != nil {
+		return models.Thread{}, err
+	}
+	defer tx.Rollback()
+
+	commandTag, err := tx.Exec(DeletePostTreeQuery, postData.Thread, postData.Id)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	var forumId int64
+	err = tx.QueryRow(UpdateForumsPostsCountQuery, -commandTag.RowsAffected(), postData.Forum).Scan(&forumId)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	var updatedThread models.Thread
+	err = tx.QueryRow(RefreshThreadPostsStatsQuery, postData.Thread).Scan(threadFields(&updatedThread)...)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	err = addOutboxEvent(tx, "post", strconv.FormatInt(postData.Id, 10), models.OutboxPostDeleted, postData)
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Thread{}, err
+	}
+	return updatedThread, nil
+}
+
+func (pfr *PostgreForumRepo) GetForumDailyStats(forumId int64, days int) ([]models.DailyStats, error) {
+	findedStats := make([]models.DailyStats, 0)
+	rows, err := pfr.Conn.Query(GetForumDailyStatsQuery, forumId, days)
+	if err != nil {
+		return []models.DailyStats{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curStats models.DailyStats
+		err := rows.Scan(&curStats.Day, &curStats.Posts, &curStats.Threads)
+		if err != nil {
+			return []models.DailyStats{}, err
+		}
+		findedStats = append(findedStats, curStats)
+	}
+	return findedStats, nil
+}
+
+func (pfr *PostgreForumRepo) GetTopPosters(forumId int64, limit int) ([]models.UserStats, error) {
+	return pfr.findUserStats(GetTopPostersQuery, forumId, limit)
+}
+
+// GetTopThreads returns the threads of the forum that got the most votes in
+// the last days, from the daily votes rollup.
+func (pfr *PostgreForumRepo) GetTopThreads(forumId int64, days int, limit int) ([]models.Thread, error) {
+	findedThreads := make([]models.Thread, 0)
+	rows, err := pfr.Conn.Query(GetTopThreadsQuery, forumId, days, limit)
+	if err != nil {
+		return []models.Thread{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curThread models.Thread
+		err := rows.Scan(threadFields(&curThread)...)
+		if err != nil {
+			return []models.Thread{}, err
+		}
+		findedThreads = append(findedThreads, curThread)
+	}
+	return findedThreads, nil
+}
+
+func (pfr *PostgreForumRepo) CountActiveUsers(forumId int64) (models.ActiveUsers, error) {
+	var activeUsers models.ActiveUsers
+	err := pfr.Conn.QueryRow(CountActiveUsersQuery, forumId).Scan(&activeUsers.Day, &activeUsers.Week, &activeUsers.Month)
+	if err != nil {
+		return models.ActiveUsers{}, err
+	}
+	return activeUsers, nil
+}
+
+var leaderboardSortKeys = map[string]string{
+	"posts":   "posts",
+	"threads": "threads",
+}
+
+func (pfr *PostgreForumRepo) GetLeaderboard(sort string, limit int) ([]models.UserStats, error) {
+	sortKey, ok := leaderboardSortKeys[sort]
+	if !ok {
+		return []models.UserStats{}, errors.New("undefined sort type")
+	}
+	sqlQuery := GetLeaderboardStartQuery + fmt.Sprintf(" WHERE s.%s > 0 ORDER BY s.%s DESC, u.nickname LIMIT $1;", sortKey, sortKey)
+	return pfr.findUserStats(sqlQuery, limit)
+}
+
+func (pfr *PostgreForumRepo) findUserStats(sqlQuery string, values ...interface{}) ([]models.UserStats, error) {
+	findedStats := make([]models.UserStats, 0)
+	rows, err := pfr.Conn.Query(sqlQuery, values...)
+	if err != nil {
+		return []models.UserStats{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curStats models.UserStats
+		err := rows.Scan(&curStats.Nickname, &curStats.Posts, &curStats.Threads)
+		if err != nil {
+			return []models.UserStats{}, err
+		}
+		findedStats = append(findedStats, curStats)
+	}
+	return findedStats, nil
+}
+
+// RefreshTrending replaces the ranking of the window with every thread that
+// got a positive score in it, so the forum and tag filters rank all of them.
+func (pfr *PostgreForumRepo) RefreshTrending(window string, period time.Duration) error {
+	tx, err := pfr.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	err = tx.QueryRow(LockTrendingQuery, window).Scan(&locked)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return nil
+	}
+
+	_, err = tx.Exec(DeleteTrendingQuery, window)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(RefreshTrendingQuery, window, int64(period/time.Second))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (pfr *PostgreForumRepo) GetTrending(filter models.TrendingFilter) ([]models.TrendingThread, error) {
+	findedThreads := make([]models.TrendingThread, 0)
+	values := []interface{}{filter.Window}
+	sqlQuery := GetTrendingStartQuery
+	if filter.Forum != "" {
+		values = append(values, filter.Forum)
+		sqlQuery += fmt.Sprintf(" AND forum = $%d", len(values))
+	}
+	if len(filter.Tags) > 0 {
+		values = append(values, filter.Tags)
+		if filter.MatchAllTags {
+			sqlQuery += fmt.Sprintf(" AND tags @> $%d", len(values))
+		} else {
+			sqlQuery += fmt.Sprintf(" AND tags && $%d", len(values))
+		}
+	}
+	if filter.AnonymousOnly {
+		sqlQuery += " AND forum NOT IN (" + SearchPrivateForumsQuery + ")"
+	}
+	sqlQuery += fmt.Sprintf(" ORDER BY score DESC, thread_id LIMIT %d;", filter.Limit)
+
+	rows, err := pfr.Conn.Query(sqlQuery, values...)
+	if err != nil {
+		return []models.TrendingThread{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var curThread models.TrendingThread
+		fields := append(threadFields(&curThread.Thread), &curThread.Score, &curThread.Posts, &curThread.Votes, &curThread.Computed)
+		err := rows.Scan(fields...)
+		if err != nil {
+			return []models.TrendingThread{}, err
+		}
+		findedThreads = append(findedThreads, curThread)
+	}
+	return findedThreads, nil
+}
+
 func (pfr *PostgreForumRepo) ServiceStatus() (models.Status, error) {
 	var curServiceStatus models.Status
 	err := pfr.Conn.QueryRow(
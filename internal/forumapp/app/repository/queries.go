@@ -1,36 +1,365 @@
 package repository
 
+// streamEventsChannel is notified by the stream_events insert trigger.
+const streamEventsChannel = "stream_events"
+
+const forumColumns = "id, title, username, slug, posts, threads, description, parent, position, is_category"
+
+const forumSubtreeQuery = `WITH RECURSIVE subtree AS (
+								SELECT slug FROM forums WHERE slug = $1
+								UNION ALL
+								SELECT f.slug FROM forums f JOIN subtree s ON f.parent = s.slug WHERE NOT f.archived
+							) SELECT slug FROM subtree`
+
+const threadColumns = "id, title, author, forum, message, votes, slug, created, pinned, pin_order, announcement, posts, last_post_id, last_post_author, last_post_at, moved_to, tags"
+
+// Each branch of the search union yields kind, id, thread, forum, author, title,
+// message, rank and created; the outer query pages and highlights the matches.
+const searchThreadsQuery = `SELECT 'thread' AS kind, t.id, t.id AS thread, t.forum, t.author, t.title, t.message,
+								ts_rank(t.search_vector, q) AS rank, t.created
+							FROM threads t, plainto_tsquery('english', $1) q
+							WHERE t.search_vector @@ q AND t.moved_to = 0`
+
+const searchPostsQuery = `SELECT 'post' AS kind, p.id, p.thread, p.forum, p.author, t.title, p.message,
+								ts_rank(p.search_vector, q) AS rank, p.created
+							FROM posts p JOIN threads t ON t.id = p.thread, plainto_tsquery('english', $1) q
+							WHERE p.search_vector @@ q`
+
+// unreadPostCondition keeps the posts p of thread $1 that the user $3 hasn't
+// read, neither up to the marker $2 nor past it.
+const unreadPostCondition = `p.thread = $1 AND p.id > $2 AND NOT EXISTS (
+								SELECT 1 FROM thread_read_posts rp WHERE rp.user_id = $3 AND rp.thread_id = $1 AND rp.post_id = p.id)`
+
+const webhookColumns = "w.id, f.slug, w.url, w.secret, w.events, w.active, w.created"
+
+const webhookDeliveryColumns = "d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.next_attempt, d.status_code, d.error, d.created, d.delivered_at"
+
 const (
-	FindUserByNicknameQuery        = "SELECT id, nickname, about, email, fullname FROM users WHERE nickname = $1;"
+	FindUserByNicknameQuery        = "SELECT id, nickname, about, email, fullname, is_admin, created FROM users WHERE nickname = $1;"
 	FindUserByEmailOrNicknameQuery = "SELECT nickname, about, email, fullname FROM users WHERE email = $1 OR nickname = $2;"
 	CreateUserQuery                = `INSERT INTO users (nickname, fullname, about, email)
 				  			   		  VALUES ($1, $2, $3, $4) RETURNING nickname, fullname, about, email;`
 	UpdateUserQuery  = "UPDATE users SET fullname = $2, about = $3, email = $4 WHERE nickname = $1 RETURNING nickname, fullname, about, email;"
-	CreateForumQuery = `INSERT INTO forums (title, username, slug)
-				  		VALUES ($1, $2, $3) RETURNING title, username, slug, posts, threads;`
-	FindForumBySlugQuery = "SELECT id, title, username, slug, posts, threads FROM forums WHERE slug = $1;"
-	CreateThreadQuery    = `INSERT INTO threads (title, author, forum, message, slug, created)
-								 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, title, author, forum, message, votes, slug, created;`
-	UpdateForumsThreadCountQuery = "UPDATE forums SET threads = threads + 1 WHERE slug = $1 RETURNING id;"
+	CreateForumQuery = `INSERT INTO forums (title, username, slug, description, parent, position, is_category)
+				  		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING title, username, slug, posts, threads, description, parent, position, is_category;`
+	FindForumBySlugQuery       = "SELECT " + forumColumns + " FROM forums WHERE slug = $1 AND NOT archived;"
+	FindForumsQuery            = "SELECT " + forumColumns + " FROM forums WHERE NOT archived"
+	FindForumTreeQuery         = "SELECT " + forumColumns + " FROM forums WHERE NOT archived ORDER BY position, title;"
+	IsForumInSubtreeQuery      = "SELECT EXISTS (SELECT 1 FROM (" + forumSubtreeQuery + ") subtree WHERE slug = $2);"
+	ReparentForumChildrenQuery = "UPDATE forums SET parent = $2 WHERE parent = $1;"
+	GetForumSettingsQuery      = `SELECT f.description, COALESCE(s.rules, ''), COALESCE(s.read_only, false), COALESCE(s.anonymous_read, true),
+								COALESCE(s.min_account_age, 0), COALESCE(s.max_post_length, 0), COALESCE(s.allowed_tags, '{}'),
+								COALESCE(s.anonymous_votes, false), COALESCE(s.min_reputation, 0)
+							FROM forums f LEFT JOIN forum_settings s ON s.forum_id = f.id WHERE f.id = $1;`
+	UpdateForumSettingsQuery = `INSERT INTO forum_settings (forum_id, rules, read_only, anonymous_read, min_account_age, max_post_length, allowed_tags, anonymous_votes, min_reputation)
+								VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+								ON CONFLICT (forum_id) DO UPDATE SET rules = EXCLUDED.rules, read_only = EXCLUDED.read_only,
+									anonymous_read = EXCLUDED.anonymous_read, min_account_age = EXCLUDED.min_account_age,
+									max_post_length = EXCLUDED.max_post_length, allowed_tags = EXCLUDED.allowed_tags,
+									anonymous_votes = EXCLUDED.anonymous_votes, min_reputation = EXCLUDED.min_reputation;`
+	UpdateForumDescriptionQuery = "UPDATE forums SET description = $2 WHERE id = $1;"
+	UpdateForumQuery            = "UPDATE forums SET title = $2, description = $3, parent = $4, position = $5 WHERE id = $1 RETURNING " + forumColumns + ";"
+	DeleteForumVotesQuery       = "DELETE FROM votes WHERE thread_id IN (SELECT id FROM threads WHERE forum = $1);"
+	DeleteForumPostsQuery       = "DELETE FROM posts WHERE forum = $1;"
+	DeleteForumThreadsQuery     = "DELETE FROM threads WHERE forum = $1;"
+	DeleteForumUsersQuery       = "DELETE FROM forum_users WHERE forum_id = $1;"
+	ArchiveForumQuery           = "UPDATE forums SET archived = true, posts = 0, threads = 0 WHERE id = $1;"
+	CreateThreadQuery           = `INSERT INTO threads (title, author, forum, message, slug, created, last_post_at, tags)
+								 VALUES ($1, $2, $3, $4, $5, $6, $6, $7) RETURNING ` + threadColumns + ";"
+	UpdateForumsThreadCountQuery = "UPDATE forums SET threads = threads + 1, last_activity = now() WHERE slug = $1 RETURNING id;"
 	UpdateForumsPostsCountQuery  = "UPDATE forums SET posts = posts + $1 WHERE slug = $2 RETURNING id;"
-	FindThreadBySlugQuery        = "SELECT id, title, author, forum, message, votes, slug, created FROM threads WHERE slug = $1;"
-	FindThreadBySlugOrIdQuery    = "SELECT id, title, author, forum, message, votes, slug, created FROM threads WHERE id = $1 OR (slug = $2 AND slug <> '');"
-	FindThreadByIdQuery          = "SELECT id, title, author, forum, message, votes, slug, created FROM threads WHERE id = $1;"
-	FindThreadsByForumQuery      = "SELECT id, title, author, forum, message, votes, slug, created FROM threads WHERE forum = $1"
-	CreateThreadStartQuery       = "INSERT INTO posts (id, parent, path, author, message, forum, thread, created) VALUES "
-	FindParentIdForPostQuery     = "SELECT thread FROM posts WHERE id = $1;"
-	FindVoteQuery                = "SELECT id FROM votes WHERE user_id = $1 AND thread_id = $2;"
-	UpdateVoteQuery              = "UPDATE votes SET voice = $3 WHERE user_id = $1 AND thread_id = $2 RETURNING id;"
-	AddVoteQuery                 = "INSERT INTO votes (user_id, thread_id, voice) VALUES ($1, $2, $3) RETURNING id;"
-	GetPostsStartQuery           = "SELECT id, parent, author, message, isEdited, forum, thread, created FROM posts WHERE thread = $1"
-	UpdateThreadQuery            = "UPDATE threads SET title = $1, message = $2 WHERE id = $3 RETURNING id, title, author, forum, message, votes, slug, created;"
-	GetForumUsersStartQuery      = "SELECT id, nickname, about, email, fullname FROM users WHERE id IN (SELECT user_id FROM forum_users WHERE forum_id = $1)"
-	GetPostInfoQuery             = "SELECT id, parent, author, message, isEdited, forum, thread, created FROM posts WHERE id = $1;"
-	UpdatePostQuery              = "UPDATE posts SET parent = $2, author = $3, message = $4, isEdited = $5, forum = $6, thread = $7, created = $8 WHERE id = $1 RETURNING id, parent, author, message, isEdited, forum, thread, created;"
-	GetServiceStatusQuery        = `SELECT
+	UpdateForumsActivityQuery    = "UPDATE forums SET posts = posts + $1, last_activity = $3 WHERE slug = $2 RETURNING id;"
+	UpdateThreadPostsCountQuery  = "UPDATE threads SET posts = posts + $1, last_post_id = $2, last_post_author = $3, last_post_at = $4 WHERE id = $5;"
+	RefreshThreadPostsStatsQuery = `UPDATE threads SET
+									posts = (SELECT COUNT(*) FROM posts WHERE thread = $1),
+									last_post_id = COALESCE((SELECT id FROM posts WHERE thread = $1 ORDER BY created DESC, id DESC LIMIT 1), 0),
+									last_post_author = COALESCE((SELECT author FROM posts WHERE thread = $1 ORDER BY created DESC, id DESC LIMIT 1), ''),
+									last_post_at = COALESCE((SELECT created FROM posts WHERE thread = $1 ORDER BY created DESC, id DESC LIMIT 1), created)
+								WHERE id = $1 RETURNING ` + threadColumns + ";"
+	FindThreadBySlugQuery       = "SELECT " + threadColumns + " FROM threads WHERE slug = $1;"
+	FindThreadBySlugOrIdQuery   = "SELECT " + threadColumns + " FROM threads WHERE id = $1 OR (slug = $2 AND slug <> '');"
+	FindThreadByIdQuery         = "SELECT " + threadColumns + " FROM threads WHERE id = $1;"
+	FindThreadsByForumQuery     = "SELECT " + threadColumns + " FROM threads WHERE forum = $1 AND NOT pinned AND NOT announcement"
+	FindThreadsByForumTreeQuery = "SELECT " + threadColumns + " FROM threads WHERE forum IN (" + forumSubtreeQuery + ") AND NOT pinned AND NOT announcement"
+	GetForumTagsQuery           = `SELECT tag, COUNT(*) FROM threads, unnest(tags) AS tag
+						WHERE forum = $1 AND moved_to = 0
+						GROUP BY tag ORDER BY COUNT(*) DESC, tag LIMIT $2;`
+	FindPinnedThreadsQuery = `SELECT ` + threadColumns + ` FROM threads
+								 WHERE (forum = $1 AND pinned) OR announcement
+								 ORDER BY announcement DESC, pin_order, created DESC;`
+	FindPinnedThreadsTreeQuery = `SELECT ` + threadColumns + ` FROM threads
+								 WHERE (forum IN (` + forumSubtreeQuery + `) AND pinned) OR announcement
+								 ORDER BY announcement DESC, pin_order, created DESC;`
+	CountPinnedThreadsQuery = `SELECT (SELECT COUNT(*) FROM threads WHERE forum = $1 AND pinned),
+								(SELECT COUNT(*) FROM threads WHERE announcement);`
+	PinThreadQuery           = "UPDATE threads SET pinned = $2, pin_order = $3, announcement = $4 WHERE id = $1 RETURNING " + threadColumns + ";"
+	CreateThreadStartQuery   = "INSERT INTO posts (id, parent, path, author, message, forum, thread, created) VALUES "
+	FindParentIdForPostQuery = "SELECT thread FROM posts WHERE id = $1;"
+	// a vote that doesn't change the voice returns no row
+	SaveVoteQuery = `INSERT INTO votes (user_id, thread_id, voice) VALUES ($1, $2, $3)
+								ON CONFLICT (user_id, thread_id) DO UPDATE SET voice = EXCLUDED.voice, created = now()
+								WHERE votes.voice <> EXCLUDED.voice RETURNING id;`
+	DeleteVoteQuery        = "DELETE FROM votes WHERE user_id = $1 AND thread_id = $2;"
+	GetUserReputationQuery = `SELECT f.slug, ROUND(decayed_reputation(r.score, r.updated))::int
+								FROM user_reputation r JOIN forums f ON f.id = r.forum_id
+								WHERE r.user_id = $1 AND NOT f.archived ORDER BY 2 DESC, f.slug;`
+	CountThreadVotesQuery     = "SELECT COUNT(*) FILTER (WHERE voice > 0), COUNT(*) FILTER (WHERE voice < 0) FROM votes WHERE thread_id = $1;"
+	GetThreadVotersStartQuery = "SELECT v.id, u.nickname, v.voice, v.created FROM votes v JOIN users u ON u.id = v.user_id WHERE v.thread_id = $1"
+	GetUserVotesStartQuery    = `SELECT v.id, t.id, COALESCE(t.slug, ''), t.title, t.forum, v.voice, v.created
+								FROM votes v JOIN threads t ON t.id = v.thread_id WHERE v.user_id = $1`
+	SavePostVoteQuery = `INSERT INTO post_votes (user_id, post_id, voice) VALUES ($1, $2, $3)
+								ON CONFLICT (user_id, post_id) DO UPDATE SET voice = EXCLUDED.voice, created = now()
+								WHERE post_votes.voice <> EXCLUDED.voice;`
+	DeletePostVoteQuery     = "DELETE FROM post_votes WHERE user_id = $1 AND post_id = $2;"
+	AddPostReactionQuery    = "INSERT INTO post_reactions (user_id, post_id, emoji) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING;"
+	DeletePostReactionQuery = "DELETE FROM post_reactions WHERE user_id = $1 AND post_id = $2 AND emoji = $3;"
+	GetPostReactionsQuery   = `SELECT r.emoji, COUNT(*), array_agg(u.nickname ORDER BY r.created, r.user_id)
+								FROM post_reactions r JOIN users u ON u.id = r.user_id
+								WHERE r.post_id = $1 GROUP BY r.emoji ORDER BY COUNT(*) DESC, MIN(r.created);`
+	GetPostsStartQuery      = "SELECT id, parent, author, message, isEdited, forum, thread, created, votes, reactions FROM posts WHERE thread = $1"
+	UpdateThreadQuery       = "UPDATE threads SET title = $1, message = $2, tags = $4 WHERE id = $3 RETURNING " + threadColumns + ";"
+	GetForumUsersStartQuery = "SELECT id, nickname, about, email, fullname FROM users WHERE id IN (SELECT user_id FROM forum_users WHERE forum_id = $1)"
+	// $1 is the raw query for trigram similarity, $2 the escaped LIKE prefix pattern
+	SearchUsersStartQuery = `SELECT id, nickname, about, email, fullname FROM users
+								WHERE (nickname::text ILIKE $2 OR fullname::text ILIKE $2 OR nickname::text % $1 OR fullname::text % $1)`
+	SearchUsersEndQuery = ` ORDER BY nickname::text ILIKE $2 DESC,
+								greatest(similarity(nickname::text, $1), similarity(fullname::text, $1)) DESC, nickname`
+	DeletePostMentionsQuery = `DELETE FROM mentions WHERE post_id = $1
+								AND user_id NOT IN (SELECT id FROM users WHERE nickname = ANY($2::citext[]));`
+	AddPostMentionsQuery = `WITH added AS (
+								INSERT INTO mentions (post_id, user_id)
+								SELECT $1, id FROM users WHERE nickname = ANY($2::citext[])
+								ON CONFLICT DO NOTHING RETURNING user_id
+							) SELECT u.nickname FROM users u JOIN added a ON a.user_id = u.id;`
+	// $1 and $2 pair the posts with the nicknames mentioned in them
+	AddPostsMentionsQuery = `INSERT INTO mentions (post_id, user_id)
+								SELECT m.post_id, u.id FROM unnest($1::bigint[], $2::citext[]) AS m(post_id, nickname)
+								JOIN users u ON u.nickname = m.nickname
+								ON CONFLICT DO NOTHING;`
+	// a vote of the user $1 notifies the author of the thread $2 unless it is
+	// their own, without the voter in forums with anonymous votes
+	AddVoteNotificationQuery = `INSERT INTO notifications (user_id, type, actor, thread)
+								SELECT u.id, 'vote', CASE WHEN COALESCE(s.anonymous_votes, false) THEN '' ELSE voter.nickname END, t.id
+								FROM threads t
+								JOIN users u ON u.nickname = t.author
+								JOIN users voter ON voter.id = $1
+								JOIN forums f ON f.slug = t.forum
+								LEFT JOIN forum_settings s ON s.forum_id = f.id
+								LEFT JOIN notification_preferences np ON np.user_id = u.id
+								WHERE t.id = $2 AND u.id <> $1 AND COALESCE(np.vote, true);`
+	// a new post notifies the author of its parent, the author of its thread and
+	// the users mentioned in it, each of them once and for the first reason
+	AddPostsNotificationsQuery = `INSERT INTO notifications (user_id, type, actor, thread, post)
+								SELECT n.user_id, n.type, n.actor, n.thread, n.post FROM (
+									SELECT DISTINCT ON (c.post, u.id) u.id AS user_id, c.type, c.actor, c.thread, c.post
+									FROM (
+										SELECT p.id AS post, p.thread, p.author AS actor, pp.author AS recipient, 'reply'::text AS type, 1 AS reason
+										FROM posts p JOIN posts pp ON pp.id = p.parent WHERE p.id = ANY($1::bigint[])
+										UNION ALL
+										SELECT p.id, p.thread, p.author, t.author, 'thread_post', 2
+										FROM posts p JOIN threads t ON t.id = p.thread WHERE p.id = ANY($1::bigint[])
+										UNION ALL
+										SELECT p.id, p.thread, p.author, mu.nickname, 'mention', 3
+										FROM posts p JOIN mentions m ON m.post_id = p.id JOIN users mu ON mu.id = m.user_id
+										WHERE p.id = ANY($1::bigint[])
+									) c JOIN users u ON u.nickname = c.recipient
+									WHERE u.nickname <> c.actor
+									ORDER BY c.post, u.id, c.reason
+								) n LEFT JOIN notification_preferences np ON np.user_id = n.user_id
+								WHERE COALESCE(CASE n.type
+									WHEN 'reply' THEN np.reply
+									WHEN 'thread_post' THEN np.thread_post
+									WHEN 'mention' THEN np.mention
+								END, true)
+								ORDER BY n.post, n.user_id;`
+	AddMentionNotificationsQuery = `INSERT INTO notifications (user_id, type, actor, thread, post)
+								SELECT u.id, 'mention', $2, $3, $4 FROM users u
+								LEFT JOIN notification_preferences np ON np.user_id = u.id
+								WHERE u.nickname = ANY($1::citext[]) AND COALESCE(np.mention, true);`
+	GetNotificationsStartQuery      = "SELECT id, type, actor, thread, post, is_read, created FROM notifications WHERE user_id = $1"
+	CountUnreadNotificationsQuery   = "SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND NOT is_read;"
+	MarkAllNotificationsReadQuery   = "UPDATE notifications SET is_read = true WHERE user_id = $1 AND NOT is_read;"
+	MarkNotificationsReadQuery      = "UPDATE notifications SET is_read = true WHERE user_id = $1 AND id = ANY($2::bigint[]);"
+	GetNotificationPreferencesQuery = `SELECT COALESCE(np.reply, true), COALESCE(np.thread_post, true), COALESCE(np.vote, true), COALESCE(np.mention, true)
+								FROM users u LEFT JOIN notification_preferences np ON np.user_id = u.id WHERE u.id = $1;`
+	UpdateNotificationPreferencesQuery = `INSERT INTO notification_preferences (user_id, reply, thread_post, vote, mention)
+								VALUES ($1, $2, $3, $4, $5)
+								ON CONFLICT (user_id) DO UPDATE SET reply = EXCLUDED.reply, thread_post = EXCLUDED.thread_post,
+									vote = EXCLUDED.vote, mention = EXCLUDED.mention;`
+	GetUserMentionsStartQuery = `SELECT p.id, p.parent, p.author, p.message, p.isEdited, p.forum, p.thread, p.created
+								FROM posts p JOIN mentions m ON m.post_id = p.id WHERE m.user_id = $1`
+	SubscribeThreadQuery = `INSERT INTO thread_subscriptions (user_id, thread_id)
+								SELECT id, $2 FROM users WHERE nickname = ANY($1::citext[])
+								ON CONFLICT DO NOTHING;`
+	UnsubscribeThreadQuery  = "DELETE FROM thread_subscriptions WHERE user_id = $1 AND thread_id = $2;"
+	IsThreadSubscribedQuery = `SELECT EXISTS (SELECT 1 FROM thread_subscriptions ts JOIN users u ON u.id = ts.user_id
+								WHERE u.nickname = $1 AND ts.thread_id = $2);`
+	SubscribeForumQuery   = "INSERT INTO forum_subscriptions (user_id, forum_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;"
+	UnsubscribeForumQuery = "DELETE FROM forum_subscriptions WHERE user_id = $1 AND forum_id = $2;"
+	// only posts written after the user subscribed to their thread or forum get into the feed
+	GetFeedQuery = `SELECT p.id, p.parent, p.author, p.message, p.isEdited, p.forum, p.thread, p.created FROM posts p
+								WHERE p.id > $2 AND p.author <> $3 AND (
+									EXISTS (SELECT 1 FROM thread_subscriptions ts
+										WHERE ts.user_id = $1 AND ts.thread_id = p.thread AND ts.created <= p.created)
+									OR EXISTS (SELECT 1 FROM forum_subscriptions fs JOIN forums f ON f.id = fs.forum_id
+										WHERE fs.user_id = $1 AND f.slug = p.forum AND fs.created <= p.created)
+								) ORDER BY p.id LIMIT $4;`
+	GetFeedCursorQuery    = "SELECT COALESCE((SELECT last_post_id FROM feed_cursors WHERE user_id = $1), 0);"
+	UpdateFeedCursorQuery = `INSERT INTO feed_cursors (user_id, last_post_id) VALUES ($1, $2)
+								ON CONFLICT (user_id) DO UPDATE SET last_post_id = GREATEST(feed_cursors.last_post_id, EXCLUDED.last_post_id);`
+	GetThreadReadQuery = "SELECT COALESCE((SELECT last_post_id FROM thread_reads WHERE user_id = $1 AND thread_id = $2), 0);"
+	GetReadPostsQuery  = "SELECT post_id FROM thread_read_posts WHERE user_id = $1 AND thread_id = $2 ORDER BY post_id;"
+	// the marker never goes back; the posts read past it are kept until it
+	// reaches them
+	MarkThreadReadQuery = `WITH marked AS (
+									INSERT INTO thread_reads (user_id, thread_id, last_post_id) VALUES ($1, $2, $3)
+									ON CONFLICT (user_id, thread_id) DO UPDATE SET last_post_id = GREATEST(thread_reads.last_post_id, EXCLUDED.last_post_id)
+									RETURNING last_post_id
+								), pruned AS (
+									DELETE FROM thread_read_posts rp USING marked
+									WHERE rp.user_id = $1 AND rp.thread_id = $2 AND rp.post_id <= marked.last_post_id
+								), added AS (
+									INSERT INTO thread_read_posts (user_id, thread_id, post_id)
+									SELECT $1, $2, r.id FROM unnest($4::bigint[]) AS r(id), marked WHERE r.id > marked.last_post_id
+									ON CONFLICT DO NOTHING
+								) SELECT last_post_id FROM marked;`
+	CountUnreadPostsQuery = `SELECT t.id, COUNT(p.id) FROM unnest($2::bigint[]) AS t(id)
+								LEFT JOIN thread_reads tr ON tr.thread_id = t.id AND tr.user_id = $1
+								LEFT JOIN posts p ON p.thread = t.id AND p.id > COALESCE(tr.last_post_id, 0) AND NOT EXISTS (
+									SELECT 1 FROM thread_read_posts rp WHERE rp.user_id = $1 AND rp.thread_id = t.id AND rp.post_id = p.id)
+								GROUP BY t.id;`
+	FindFirstUnreadFlatQuery  = "SELECT p.id FROM posts p WHERE " + unreadPostCondition + " ORDER BY p.created, p.id LIMIT 1;"
+	FindFirstUnreadTreeQuery  = "SELECT p.id FROM posts p WHERE " + unreadPostCondition + " ORDER BY p.path LIMIT 1;"
+	FindFirstUnlistedQuery    = "SELECT COALESCE(MIN(id), 0) FROM posts WHERE thread = $1 AND id > $2 AND id <> ALL($3::bigint[]);"
+	GetPostInfoQuery          = "SELECT id, parent, author, message, isEdited, forum, thread, created, votes, reactions FROM posts WHERE id = $1;"
+	MoveThreadQuery           = "UPDATE threads SET forum = $2 WHERE id = $1 RETURNING " + threadColumns + ";"
+	MoveThreadPostsQuery      = "UPDATE posts SET forum = $2 WHERE thread = $1;"
+	UpdateForumsCountersQuery = "UPDATE forums SET threads = threads + $2, posts = posts + $3 WHERE slug = $1;"
+	AddThreadForumUsersQuery  = `INSERT INTO forum_users (user_id, forum_id)
+								SELECT u.id, $2 FROM users u
+								WHERE u.nickname IN (SELECT author FROM threads WHERE id = $1 UNION SELECT author FROM posts WHERE thread = $1)
+								AND NOT EXISTS (SELECT 1 FROM forum_users fu WHERE fu.user_id = u.id AND fu.forum_id = $2);`
+	RemoveThreadForumUsersQuery = `DELETE FROM forum_users fu USING users u
+								WHERE fu.user_id = u.id AND fu.forum_id = $2
+								AND u.nickname IN (SELECT author FROM threads WHERE id = $1 UNION SELECT author FROM posts WHERE thread = $1)
+								AND NOT EXISTS (SELECT 1 FROM threads t WHERE t.forum = $3 AND t.author = u.nickname AND t.moved_to = 0)
+								AND NOT EXISTS (SELECT 1 FROM posts p WHERE p.forum = $3 AND p.author = u.nickname);`
+	// the votes on the thread and its posts, decayed as in user_reputation,
+	// move from the reputation of their authors in the forum $2 to the forum $3
+	MoveThreadReputationQuery = `WITH earned AS (
+									SELECT u.id AS user_id, decayed_reputation(v.voice, v.created) AS score
+									FROM votes v JOIN threads t ON t.id = v.thread_id JOIN users u ON u.nickname = t.author
+									WHERE v.thread_id = $1 AND v.user_id <> u.id
+									UNION ALL
+									SELECT u.id, decayed_reputation(pv.voice, pv.created)
+									FROM post_votes pv JOIN posts p ON p.id = pv.post_id JOIN users u ON u.nickname = p.author
+									WHERE p.thread = $1 AND pv.user_id <> u.id
+								), moved AS (
+									SELECT user_id, SUM(score) AS score FROM earned GROUP BY user_id
+								), taken AS (
+									UPDATE user_reputation r SET score = decayed_reputation(r.score, r.updated) - m.score, updated = now()
+									FROM moved m WHERE r.user_id = m.user_id AND r.forum_id = $2
+								) INSERT INTO user_reputation (user_id, forum_id, score) SELECT user_id, $3, score FROM moved
+								ON CONFLICT (user_id, forum_id) DO UPDATE
+								SET score = decayed_reputation(user_reputation.score, user_reputation.updated) + EXCLUDED.score, updated = now();`
+	CreateRedirectThreadQuery = `INSERT INTO threads (title, author, forum, message, slug, created, last_post_at, moved_to)
+								VALUES ($1, $2, $3, $4, '', $5, $5, $6);`
+	CreateMergeRootPostQuery = `INSERT INTO posts (id, parent, path, author, message, forum, thread, created)
+								VALUES (nextval('posts_id_seq'::regclass), 0, ARRAY[currval(pg_get_serial_sequence('posts', 'id'))::bigint], $1, $2, $3, $4, $5)
+								RETURNING id, parent, author, message, isEdited, forum, thread, created;`
+	MergeThreadPostsQuery = `UPDATE posts SET thread = $2, forum = $3, path = ARRAY[$4::bigint] || path,
+								parent = CASE WHEN parent = 0 THEN $4 ELSE parent END
+							WHERE thread = $1;`
+	CloseMergedThreadQuery = `UPDATE threads SET moved_to = $2, votes = 0, posts = 0, last_post_id = 0, last_post_author = ''
+							WHERE id = $1;`
+	DeleteThreadVotesQuery = "DELETE FROM votes WHERE thread_id = $1;"
+	SplitPostTreeQuery     = `UPDATE posts SET thread = $2, path = path[array_position(path, $3::bigint) : array_length(path, 1)],
+								parent = CASE WHEN id = $3 THEN 0 ELSE parent END
+							WHERE thread = $1 AND path @> ARRAY[$3::bigint];`
+	DeletePostTreeQuery       = "DELETE FROM posts WHERE thread = $1 AND path @> ARRAY[$2::bigint];"
+	UpdatePostQuery           = "UPDATE posts SET parent = $2, author = $3, message = $4, isEdited = $5, forum = $6, thread = $7, created = $8 WHERE id = $1 RETURNING id, parent, author, message, isEdited, forum, thread, created;"
+	SearchStartQuery          = "SELECT kind, id, thread, forum, author, title, ts_headline('english', message, plainto_tsquery('english', $1), 'MaxFragments=2, MaxWords=30, MinWords=10'), rank, created FROM ("
+	SearchMatchesQuery        = "SELECT * FROM (" + searchThreadsQuery + " UNION ALL " + searchPostsQuery + ") matches WHERE true"
+	SearchEndQuery            = ") results ORDER BY rank DESC, created DESC, id;"
+	SearchPrivateForumsQuery  = "SELECT f.slug FROM forums f JOIN forum_settings s ON s.forum_id = f.id WHERE NOT s.anonymous_read"
+	GetLastStreamEventIdQuery = "SELECT COALESCE(MAX(id), 0) FROM stream_events;"
+	GetStreamEventsStartQuery = "SELECT id, channel, type, payload, created FROM stream_events WHERE id > $1"
+	DeleteStreamEventsQuery   = "DELETE FROM stream_events WHERE created < $1;"
+	CreateWebhookQuery        = `WITH w AS (
+								INSERT INTO webhooks (forum_id, url, secret, events) VALUES ($1, $2, $3, $4) RETURNING *
+							) SELECT ` + webhookColumns + ` FROM w JOIN forums f ON f.id = w.forum_id;`
+	FindWebhooksQuery             = "SELECT " + webhookColumns + " FROM webhooks w JOIN forums f ON f.id = w.forum_id WHERE w.forum_id = $1 ORDER BY w.id;"
+	FindWebhookQuery              = "SELECT " + webhookColumns + " FROM webhooks w JOIN forums f ON f.id = w.forum_id WHERE w.id = $1 AND w.forum_id = $2;"
+	DeleteWebhookQuery            = "DELETE FROM webhooks WHERE id = $1;"
+	EnqueueWebhookDeliveriesQuery = `INSERT INTO webhook_deliveries (webhook_id, event, payload)
+								SELECT w.id, $2, p.payload::json FROM webhooks w, unnest($3::text[]) WITH ORDINALITY AS p(payload, n)
+								WHERE w.forum_id = $1 AND w.active AND $2 = ANY(w.events)
+								ORDER BY p.n, w.id;`
+	GetWebhookDeliveriesStartQuery = "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries d WHERE d.webhook_id = $1"
+	RedeliverWebhookDeliveryQuery  = `INSERT INTO webhook_deliveries (webhook_id, event, payload)
+								SELECT webhook_id, event, payload FROM webhook_deliveries WHERE id = $1 AND webhook_id = $2
+								RETURNING id, webhook_id, event, payload, status, attempts, next_attempt, status_code, error, created, delivered_at;`
+	// the claimed deliveries are leased by moving their next attempt, so that
+	// other workers skip them while they are being sent
+	ClaimWebhookDeliveriesQuery = `UPDATE webhook_deliveries d SET next_attempt = now() + $2 * interval '1 second'
+								FROM webhooks w
+								WHERE w.id = d.webhook_id AND d.id IN (
+									SELECT id FROM webhook_deliveries WHERE status = 'pending' AND next_attempt <= now()
+									ORDER BY next_attempt LIMIT $1 FOR UPDATE SKIP LOCKED
+								) RETURNING ` + webhookDeliveryColumns + ", w.url, w.secret;"
+	CompleteWebhookDeliveryQuery = `UPDATE webhook_deliveries SET status = $2, attempts = $3, next_attempt = $4,
+									status_code = $5, error = $6, delivered_at = $7
+								WHERE id = $1;`
+	AddOutboxEventQuery = "INSERT INTO outbox (aggregate, aggregate_id, type, payload) VALUES ($1, $2, $3, $4);"
+	// like webhook deliveries, the claimed events are leased so that other
+	// dispatchers skip them while they are being published
+	ClaimOutboxEventsQuery = `UPDATE outbox SET locked_until = now() + $2 * interval '1 second'
+								WHERE id IN (
+									SELECT id FROM outbox WHERE published_at IS NULL AND locked_until <= now()
+									ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED
+								) RETURNING id, aggregate, aggregate_id, type, payload, created, attempts, published_to;`
+	PublishOutboxEventQuery = "UPDATE outbox SET published_at = now(), published_to = $2, attempts = attempts + 1, error = '' WHERE id = $1;"
+	RetryOutboxEventQuery   = "UPDATE outbox SET published_to = $2, attempts = attempts + 1, locked_until = $3, error = $4 WHERE id = $1;"
+	DeleteOutboxEventsQuery = "DELETE FROM outbox WHERE published_at < $1;"
+	// the rollups count days in UTC
+	GetForumDailyStatsQuery = `SELECT to_char(d.day, 'YYYY-MM-DD'), COALESCE(s.posts, 0), COALESCE(s.threads, 0)
+								FROM generate_series((now() AT TIME ZONE 'UTC')::date - ($2::int - 1), (now() AT TIME ZONE 'UTC')::date, interval '1 day') AS d(day)
+								LEFT JOIN forum_daily_stats s ON s.forum_id = $1 AND s.day = d.day::date
+								ORDER BY d.day;`
+	GetTopPostersQuery = `SELECT u.nickname, s.posts, s.threads FROM forum_user_stats s JOIN users u ON u.id = s.user_id
+								WHERE s.forum_id = $1 AND s.posts > 0 ORDER BY s.posts DESC, u.nickname LIMIT $2;`
+	GetTopThreadsQuery = `SELECT ` + threadColumns + ` FROM (
+									SELECT thread_id, SUM(votes) AS window_votes FROM thread_daily_votes
+									WHERE forum_id = $1 AND day > (now() AT TIME ZONE 'UTC')::date - $2::int
+									GROUP BY thread_id HAVING SUM(votes) > 0
+									ORDER BY window_votes DESC, thread_id DESC LIMIT $3
+								) v JOIN threads ON id = v.thread_id
+								ORDER BY v.window_votes DESC, id DESC;`
+	CountActiveUsersQuery = `SELECT COUNT(DISTINCT user_id) FILTER (WHERE day > (now() AT TIME ZONE 'UTC')::date - 1),
+									COUNT(DISTINCT user_id) FILTER (WHERE day > (now() AT TIME ZONE 'UTC')::date - 7),
+									COUNT(DISTINCT user_id)
+								FROM forum_user_daily_stats
+								WHERE forum_id = $1 AND day > (now() AT TIME ZONE 'UTC')::date - 30 AND posts + threads > 0;`
+	GetLeaderboardStartQuery = "SELECT u.nickname, s.posts, s.threads FROM user_stats s JOIN users u ON u.id = s.user_id"
+	// only one instance refreshes a window at a time, the others skip it
+	LockTrendingQuery    = "SELECT pg_try_advisory_xact_lock(hashtext('trending:' || $1));"
+	DeleteTrendingQuery  = "DELETE FROM trending_threads WHERE period = $1;"
+	RefreshTrendingQuery = `INSERT INTO trending_threads (period, thread_id, score, recent_posts, recent_votes)
+								SELECT $1, a.thread_id, (SUM(a.posts) + 2 * SUM(a.votes))::float8, SUM(a.posts)::int, SUM(a.votes)::int
+								FROM (
+									SELECT thread AS thread_id, COUNT(*) AS posts, 0 AS votes FROM posts
+									WHERE created > now() - $2 * interval '1 second' GROUP BY thread
+									UNION ALL
+									SELECT thread_id, 0, SUM(voice) FROM votes
+									WHERE created > now() - $2 * interval '1 second' GROUP BY thread_id
+								) a JOIN threads t ON t.id = a.thread_id
+								WHERE t.moved_to = 0
+								GROUP BY a.thread_id HAVING SUM(a.posts) + 2 * SUM(a.votes) > 0;`
+	GetTrendingStartQuery = "SELECT " + threadColumns + ", score, recent_posts, recent_votes, computed FROM trending_threads JOIN threads ON id = thread_id WHERE period = $1"
+	GetServiceStatusQuery = `SELECT
 									(SELECT COUNT(*) FROM forums) AS forum, 
 									(SELECT COUNT(*) FROM posts) AS post, 
 									(SELECT COUNT(*) FROM threads) AS thread, 
 									(SELECT COUNT(*) FROM users) AS user;`
-	ClearServiceQuery = "TRUNCATE forums, posts, threads, users, votes CASCADE;"
+	ClearServiceQuery = "TRUNCATE forums, posts, threads, users, votes, stream_events, outbox CASCADE;"
 )
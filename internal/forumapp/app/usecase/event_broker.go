@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"forumApp/internal/forumapp/models"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	eventsBatchSize      = 500
+	eventsGapTimeout     = time.Minute
+	eventsMaxGap         = 1000
+	eventsSubscriberSize = 64
+	eventsRetention      = 24 * time.Hour
+	eventsRetryDelay     = time.Second
+)
+
+// EventBroker fans the events stored in the database out to the subscribers of
+// their channels. Notifications only wake it up: the events are always read
+// from the table, so every API instance sees all of them.
+//
+// Ids are taken before the transactions commit, so an event may show up after
+// events with higher ids. The ids skipped below the last dispatched event are
+// kept as gaps, and dispatch re-reads from the oldest gap until its event
+// shows up or eventsGapTimeout passes, as for a rolled back insert.
+type EventBroker struct {
+	repo        models.ForumRepository
+	mu          sync.Mutex
+	subscribers map[string]map[chan models.StreamEvent]struct{}
+	lastEventId int64
+	gaps        map[int64]time.Time
+}
+
+func NewEventBroker(fr models.ForumRepository) *EventBroker {
+	return &EventBroker{
+		repo:        fr,
+		subscribers: make(map[string]map[chan models.StreamEvent]struct{}),
+		gaps:        make(map[int64]time.Time),
+	}
+}
+
+func (eb *EventBroker) Run(ctx context.Context) {
+	go eb.prune(ctx)
+
+	for {
+		lastEventId, err := eb.repo.GetLastStreamEventId()
+		if err == nil {
+			eb.lastEventId = lastEventId
+			break
+		}
+		log.Printf("event broker: %s", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventsRetryDelay):
+		}
+	}
+
+	for {
+		wakeups := make(chan struct{}, 1)
+		listenErr := make(chan error, 1)
+		go func() {
+			listenErr <- eb.repo.ListenStreamEvents(ctx, wakeups)
+		}()
+
+	listen:
+		for {
+			select {
+			case <-wakeups:
+				eb.dispatch()
+			case err := <-listenErr:
+				log.Printf("event broker: %s", err)
+				break listen
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventsRetryDelay):
+		}
+	}
+}
+
+// Subscribe returns the live events of the channel and the function to stop
+// receiving them.
+func (eb *EventBroker) Subscribe(channel string) (<-chan models.StreamEvent, func()) {
+	events := make(chan models.StreamEvent, eventsSubscriberSize)
+
+	eb.mu.Lock()
+	if eb.subscribers[channel] == nil {
+		eb.subscribers[channel] = make(map[chan models.StreamEvent]struct{})
+	}
+	eb.subscribers[channel][events] = struct{}{}
+	eb.mu.Unlock()
+
+	return events, func() {
+		eb.mu.Lock()
+		defer eb.mu.Unlock()
+		if _, ok := eb.subscribers[channel][events]; ok {
+			eb.unsubscribe(channel, events)
+		}
+	}
+}
+
+func (eb *EventBroker) unsubscribe(channel string, events chan models.StreamEvent) {
+	delete(eb.subscribers[channel], events)
+	if len(eb.subscribers[channel]) == 0 {
+		delete(eb.subscribers, channel)
+	}
+	close(events)
+}
+
+func (eb *EventBroker) dispatch() {
+	now := time.Now()
+	afterId := eb.lastEventId
+	for eventId, seen := range eb.gaps {
+		if now.Sub(seen) > eventsGapTimeout {
+			delete(eb.gaps, eventId)
+		} else if eventId <= afterId {
+			afterId = eventId - 1
+		}
+	}
+
+	for {
+		newEvents, err := eb.repo.GetStreamEvents("", afterId, eventsBatchSize)
+		if err != nil {
+			log.Printf("event broker: %s", err)
+			return
+		}
+
+		eb.mu.Lock()
+		for _, event := range newEvents {
+			afterId = event.Id
+			if event.Id > eb.lastEventId {
+				// no more inserts than eventsMaxGap are in flight, larger
+				// jumps come from the sequence, as after a truncate
+				gapStart := eb.lastEventId + 1
+				if event.Id-gapStart > eventsMaxGap {
+					gapStart = event.Id - eventsMaxGap
+				}
+				for eventId := gapStart; eventId < event.Id; eventId++ {
+					eb.gaps[eventId] = now
+				}
+				eb.lastEventId = event.Id
+			} else if _, ok := eb.gaps[event.Id]; ok {
+				delete(eb.gaps, event.Id)
+			} else {
+				// already dispatched
+				continue
+			}
+
+			for events := range eb.subscribers[event.Channel] {
+				select {
+				case events <- event:
+				default:
+					// a subscriber that can't keep up is dropped, it resumes
+					// from the stored events after reconnecting
+					eb.unsubscribe(event.Channel, events)
+				}
+			}
+		}
+		eb.mu.Unlock()
+
+		if len(newEvents) < eventsBatchSize {
+			return
+		}
+	}
+}
+
+func (eb *EventBroker) prune(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := eb.repo.DeleteStreamEvents(time.Now().Add(-eventsRetention))
+			if err != nil {
+				log.Printf("event broker: %s", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"forumApp/internal/forumapp/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// streamRepo holds the committed stream events, in the order of ids.
+type streamRepo struct {
+	models.ForumRepository
+	events []models.StreamEvent
+}
+
+func (sr *streamRepo) commit(eventIds ...int64) {
+	for _, eventId := range eventIds {
+		sr.events = append(sr.events, models.StreamEvent{Id: eventId, Channel: "thread:1"})
+	}
+	for i := len(sr.events) - 1; i > 0 && sr.events[i].Id < sr.events[i-1].Id; i-- {
+		sr.events[i], sr.events[i-1] = sr.events[i-1], sr.events[i]
+	}
+}
+
+func (sr *streamRepo) GetStreamEvents(channel string, afterId int64, limit int) ([]models.StreamEvent, error) {
+	findedEvents := make([]models.StreamEvent, 0)
+	for _, event := range sr.events {
+		if event.Id > afterId && (channel == "" || event.Channel == channel) && len(findedEvents) < limit {
+			findedEvents = append(findedEvents, event)
+		}
+	}
+	return findedEvents, nil
+}
+
+func receivedEventIds(events <-chan models.StreamEvent) []int64 {
+	eventIds := make([]int64, 0)
+	for {
+		select {
+		case event := <-events:
+			eventIds = append(eventIds, event.Id)
+		default:
+			return eventIds
+		}
+	}
+}
+
+func TestEventBrokerDispatchesLateCommits(t *testing.T) {
+	repo := &streamRepo{}
+	broker := NewEventBroker(repo)
+	events, closeStream := broker.Subscribe("thread:1")
+	defer closeStream()
+
+	repo.commit(1)
+	broker.dispatch()
+	// the event 2 is still in flight when 3 commits
+	repo.commit(3)
+	broker.dispatch()
+	repo.commit(2)
+	broker.dispatch()
+	broker.dispatch()
+
+	if got := receivedEventIds(events); !reflect.DeepEqual(got, []int64{1, 3, 2}) {
+		t.Errorf("received events %v, want [1 3 2]", got)
+	}
+	if len(broker.gaps) != 0 {
+		t.Errorf("gaps = %v, want none", broker.gaps)
+	}
+}
+
+func TestEventBrokerForgetsStaleGaps(t *testing.T) {
+	repo := &streamRepo{}
+	broker := NewEventBroker(repo)
+	events, closeStream := broker.Subscribe("thread:1")
+	defer closeStream()
+
+	// the insert of the event 2 was rolled back
+	repo.commit(1, 3)
+	broker.dispatch()
+	broker.gaps[2] = time.Now().Add(-2 * eventsGapTimeout)
+	repo.commit(4)
+	broker.dispatch()
+
+	if got := receivedEventIds(events); !reflect.DeepEqual(got, []int64{1, 3, 4}) {
+		t.Errorf("received events %v, want [1 3 4]", got)
+	}
+	if len(broker.gaps) != 0 {
+		t.Errorf("gaps = %v, want none", broker.gaps)
+	}
+}
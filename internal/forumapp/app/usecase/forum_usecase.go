@@ -1,23 +1,37 @@
 package usecase
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"forumApp/internal/forumapp/models"
 	"forumApp/internal/pkg/arrutils"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 type ForumUsecase struct {
 	ForumRepo      models.ForumRepository
+	Events         *EventBroker
+	Trending       *TrendingJob
+	reactions      []string
 	contextTimeout time.Duration
 }
 
-func NewUserUsecase(fr models.ForumRepository, timeout time.Duration) models.ForumUsecase {
+var threadSorts = []string{"created", "last_post", "votes", "replies", "hot"}
+
+func NewUserUsecase(fr models.ForumRepository, eb *EventBroker, tj *TrendingJob, reactions []string, timeout time.Duration) models.ForumUsecase {
 	return &ForumUsecase{
 		ForumRepo:      fr,
+		Events:         eb,
+		Trending:       tj,
+		reactions:      reactions,
 		contextTimeout: timeout,
 	}
 }
@@ -30,11 +44,19 @@ func (fu *ForumUsecase) CreateForum(forumData models.Forum) (models.Forum, int,
 
 	forumData.User = findedUser.Nickname
 
+	if forumData.Parent != "" {
+		parentForum, err := fu.ForumRepo.FindForumBySlug(forumData.Parent)
+		if err != nil {
+			return models.Forum{}, http.StatusNotFound, err
+		}
+		forumData.Parent = parentForum.Slug
+	}
+
 	createdForum, err := fu.ForumRepo.CreateForum(forumData)
 	if err != nil {
 		existedForum, err := fu.ForumRepo.FindForumBySlug(forumData.Slug)
 		if err != nil {
-			return models.Forum{}, http.StatusConflict, err
+			return models.Forum{}, http.StatusConflict, errors.New("Forum slug " + forumData.Slug + " is reserved")
 		}
 
 		return existedForum, http.StatusConflict, nil
@@ -43,17 +65,211 @@ func (fu *ForumUsecase) CreateForum(forumData models.Forum) (models.Forum, int,
 	return createdForum, http.StatusCreated, nil
 }
 
-func (fu *ForumUsecase) GetForum(slug string) (models.Forum, int, error) {
+func (fu *ForumUsecase) GetForum(slug string, actor string) (models.Forum, int, error) {
 	findedForum, err := fu.ForumRepo.FindForumBySlug(slug)
 	if err != nil {
 		return models.Forum{}, http.StatusNotFound, err
 	}
+	code, err := fu.checkReadAccess(actor, findedForum)
+	if err != nil {
+		return models.Forum{}, code, err
+	}
 
 	return findedForum, http.StatusOK, nil
 }
 
+var forumSorts = []string{"name", "activity", "posts"}
+
+func (fu *ForumUsecase) GetForums(params map[string][]string) (models.Forums, int, error) {
+	limit := "100"
+	if len(params["limit"]) > 0 {
+		limit = params["limit"][0]
+	}
+	if _, err := strconv.Atoi(limit); err != nil {
+		return []models.Forum{}, http.StatusBadRequest, errors.New("limit must be a number")
+	}
+	since := ""
+	if len(params["since"]) > 0 {
+		since = params["since"][0]
+	}
+	sort := "name"
+	if len(params["sort"]) > 0 {
+		sort = params["sort"][0]
+	}
+	if !arrutils.StringSliceHas(forumSorts, sort) {
+		return []models.Forum{}, http.StatusBadRequest, errors.New("undefined sort type")
+	}
+	desc := ""
+	comparisonSign := ">"
+	if len(params["desc"]) > 0 && params["desc"][0] == "true" {
+		desc = "desc"
+		comparisonSign = "<"
+	}
+
+	findedForums, err := fu.ForumRepo.FindForums(limit, since, sort, desc, comparisonSign)
+	if err != nil {
+		return []models.Forum{}, http.StatusInternalServerError, err
+	}
+
+	return findedForums, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) GetForumTree() (models.Forums, int, error) {
+	findedForums, err := fu.ForumRepo.FindForumTree()
+	if err != nil {
+		return []models.Forum{}, http.StatusInternalServerError, err
+	}
+
+	children := make(map[string][]models.Forum)
+	known := make(map[string]bool)
+	for _, forum := range findedForums {
+		known[strings.ToLower(forum.Slug)] = true
+	}
+	var roots []models.Forum
+	for _, forum := range findedForums {
+		parent := strings.ToLower(forum.Parent)
+		if forum.Parent == "" || !known[parent] {
+			roots = append(roots, forum)
+			continue
+		}
+		children[parent] = append(children[parent], forum)
+	}
+
+	var buildTree func(forum models.Forum) models.Forum
+	buildTree = func(forum models.Forum) models.Forum {
+		forum.TotalPosts = forum.Posts
+		forum.TotalThreads = forum.Threads
+		for _, child := range children[strings.ToLower(forum.Slug)] {
+			child = buildTree(child)
+			forum.TotalPosts += child.TotalPosts
+			forum.TotalThreads += child.TotalThreads
+			forum.Children = append(forum.Children, child)
+		}
+		return forum
+	}
+
+	forumTree := make([]models.Forum, 0, len(roots))
+	for _, root := range roots {
+		forumTree = append(forumTree, buildTree(root))
+	}
+
+	return forumTree, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) UpdateForum(slug string, actor string, forumData models.Forum) (models.Forum, int, error) {
+	findedForum, err := fu.ForumRepo.FindForumBySlug(slug)
+	if err != nil {
+		return models.Forum{}, http.StatusNotFound, err
+	}
+
+	code, err := fu.checkModerator(actor, findedForum)
+	if err != nil {
+		return models.Forum{}, code, err
+	}
+
+	if len(forumData.Title) != 0 {
+		findedForum.Title = forumData.Title
+	}
+	if len(forumData.Description) != 0 {
+		findedForum.Description = forumData.Description
+	}
+	if forumData.Position != 0 {
+		findedForum.Position = forumData.Position
+	}
+	if len(forumData.Parent) != 0 {
+		parentForum, err := fu.ForumRepo.FindForumBySlug(forumData.Parent)
+		if err != nil {
+			return models.Forum{}, http.StatusNotFound, err
+		}
+
+		isDescendant, err := fu.ForumRepo.IsForumInSubtree(findedForum.Slug, parentForum.Slug)
+		if err != nil {
+			return models.Forum{}, http.StatusInternalServerError, err
+		}
+		if isDescendant {
+			return models.Forum{}, http.StatusConflict, errors.New("Can't move forum " + findedForum.Slug + " into its own subforum")
+		}
+		findedForum.Parent = parentForum.Slug
+	}
+
+	updatedForum, err := fu.ForumRepo.UpdateForum(findedForum)
+	if err != nil {
+		return models.Forum{}, http.StatusInternalServerError, err
+	}
+
+	return updatedForum, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) DeleteForum(slug string, actor string) (int, error) {
+	findedForum, err := fu.ForumRepo.FindForumBySlug(slug)
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+
+	code, err := fu.checkAdmin(actor)
+	if err != nil {
+		return code, err
+	}
+
+	err = fu.ForumRepo.DeleteForum(findedForum)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) GetForumSettings(slug string, actor string) (models.ForumSettings, int, error) {
+	findedForum, err := fu.ForumRepo.FindForumBySlug(slug)
+	if err != nil {
+		return models.ForumSettings{}, http.StatusNotFound, err
+	}
+	code, err := fu.checkReadAccess(actor, findedForum)
+	if err != nil {
+		return models.ForumSettings{}, code, err
+	}
+
+	findedSettings, err := fu.ForumRepo.GetForumSettings(findedForum.Id)
+	if err != nil {
+		return models.ForumSettings{}, http.StatusInternalServerError, err
+	}
+
+	return findedSettings, http.StatusOK, nil
+}
+
+// UpdateForumSettings replaces the settings of the forum as a whole, so
+// clients are expected to send back the full document they got from
+// GetForumSettings with their changes applied.
+func (fu *ForumUsecase) UpdateForumSettings(slug string, actor string, settings models.ForumSettings) (models.ForumSettings, int, error) {
+	findedForum, err := fu.ForumRepo.FindForumBySlug(slug)
+	if err != nil {
+		return models.ForumSettings{}, http.StatusNotFound, err
+	}
+
+	code, err := fu.checkModerator(actor, findedForum)
+	if err != nil {
+		return models.ForumSettings{}, code, err
+	}
+
+	if settings.MinAccountAge < 0 || settings.MaxPostLength < 0 || settings.MinReputation < 0 {
+		return models.ForumSettings{}, http.StatusBadRequest, errors.New("limits can't be negative")
+	}
+
+	err = fu.ForumRepo.UpdateForumSettings(findedForum.Id, settings)
+	if err != nil {
+		return models.ForumSettings{}, http.StatusInternalServerError, err
+	}
+
+	updatedSettings, err := fu.ForumRepo.GetForumSettings(findedForum.Id)
+	if err != nil {
+		return models.ForumSettings{}, http.StatusInternalServerError, err
+	}
+
+	return updatedSettings, http.StatusOK, nil
+}
+
 func (fu *ForumUsecase) CreateThread(slug string, threadData models.Thread) (models.Thread, int, error) {
-	_, err := fu.ForumRepo.FindUserByNickname(threadData.Author)
+	findedUser, err := fu.ForumRepo.FindUserByNickname(threadData.Author)
 	if err != nil {
 		return models.Thread{}, http.StatusNotFound, err
 	}
@@ -62,6 +278,28 @@ func (fu *ForumUsecase) CreateThread(slug string, threadData models.Thread) (mod
 	if err != nil {
 		return models.Thread{}, http.StatusNotFound, err
 	}
+	if findedForum.Category {
+		return models.Thread{}, http.StatusForbidden, errors.New("Can't create thread in category " + findedForum.Slug)
+	}
+
+	settings, err := fu.ForumRepo.GetForumSettings(findedForum.Id)
+	if err != nil {
+		return models.Thread{}, http.StatusInternalServerError, err
+	}
+	code, err := checkPostingRules(settings, findedForum, findedUser, threadData.Message)
+	if err != nil {
+		return models.Thread{}, code, err
+	}
+	code, err = fu.checkThreadReputation(settings, findedForum, findedUser)
+	if err != nil {
+		return models.Thread{}, code, err
+	}
+
+	threadData.Tags = normalizeTags(threadData.Tags)
+	code, err = checkAllowedTags(settings, threadData.Tags)
+	if err != nil {
+		return models.Thread{}, code, err
+	}
 
 	threadData.Forum = findedForum.Slug
 
@@ -80,35 +318,274 @@ func (fu *ForumUsecase) CreateThread(slug string, threadData models.Thread) (mod
 	return createdThread, http.StatusCreated, nil
 }
 
-func (fu *ForumUsecase) GetThreads(slug string, params map[string][]string) (models.Threads, int, error) {
-	_, err := fu.ForumRepo.FindForumBySlug(slug)
+func (fu *ForumUsecase) GetThreads(slug string, actor string, params map[string][]string) (models.Threads, int, error) {
+	findedForum, err := fu.ForumRepo.FindForumBySlug(slug)
 	if err != nil {
 		return []models.Thread{}, http.StatusNotFound, err
 	}
 
-	limit := "100"
+	code, err := fu.checkReadAccess(actor, findedForum)
+	if err != nil {
+		return []models.Thread{}, code, err
+	}
+
+	limit := 100
 	if len(params["limit"]) > 0 {
-		limit = params["limit"][0]
+		limit, err = strconv.Atoi(params["limit"][0])
+		if err != nil || limit < 0 {
+			return []models.Thread{}, http.StatusBadRequest, errors.New("limit must be a non-negative number")
+		}
 	}
 	since := ""
 	if len(params["since"]) > 0 {
 		since = params["since"][0]
 	}
+	sort := "created"
+	if len(params["sort"]) > 0 {
+		sort = params["sort"][0]
+	}
+	if !arrutils.StringSliceHas(threadSorts, sort) {
+		return []models.Thread{}, http.StatusBadRequest, errors.New("undefined sort type")
+	}
 	desc := ""
 	comparisonSign := ">="
 	if len(params["desc"]) > 0 && params["desc"][0] == "true" {
 		desc = "desc"
 		comparisonSign = "<="
 	}
+	if sort != "created" {
+		if _, err := strconv.ParseInt(since, 10, 64); since != "" && err != nil {
+			return []models.Thread{}, http.StatusBadRequest, errors.New("since must be a thread id for sort " + sort)
+		}
+		comparisonSign = strings.TrimSuffix(comparisonSign, "=")
+	}
+
+	withSubforums := len(params["subforums"]) > 0 && params["subforums"][0] == "true"
+	tags, matchAllTags, err := tagParams(params)
+	if err != nil {
+		return []models.Thread{}, http.StatusBadRequest, err
+	}
+
+	// Pinned threads and announcements head the first page only, so they
+	// neither shift nor repeat in the since-paginated rest of the listing.
+	// They come on top of the limit, which counts the regular threads only,
+	// so that the last thread of a page is always the one since refers to.
+	findedThreads := make([]models.Thread, 0)
+	if since == "" {
+		pinnedThreads, err := fu.ForumRepo.FindPinnedThreads(findedForum.Slug, withSubforums)
+		if err != nil {
+			return []models.Thread{}, http.StatusInternalServerError, err
+		}
+
+		for _, thread := range pinnedThreads {
+			if hasTags(thread.Tags, tags, matchAllTags) {
+				findedThreads = append(findedThreads, thread)
+			}
+		}
+	}
 
-	findedThreads, err := fu.ForumRepo.FindThreadsBySlugWithParams(slug, limit, since, desc, comparisonSign)
+	listedThreads, err := fu.ForumRepo.FindThreadsBySlugWithParams(findedForum.Slug, strconv.Itoa(limit), since, sort, desc, comparisonSign, withSubforums, tags, matchAllTags)
 	if err != nil {
 		return []models.Thread{}, http.StatusNotFound, err
 	}
+	findedThreads = append(findedThreads, listedThreads...)
+
+	if actor != "" && len(findedThreads) > 0 {
+		reader, err := fu.ForumRepo.FindUserByNickname(actor)
+		if err == nil {
+			threadIds := make([]int64, 0, len(findedThreads))
+			for _, thread := range findedThreads {
+				threadIds = append(threadIds, thread.Id)
+			}
+			unreadPosts, err := fu.ForumRepo.CountUnreadPosts(reader.Id, threadIds)
+			if err != nil {
+				return []models.Thread{}, http.StatusInternalServerError, err
+			}
+			for i := range findedThreads {
+				unread := unreadPosts[findedThreads[i].Id]
+				findedThreads[i].Unread = &unread
+			}
+		}
+	}
 
 	return findedThreads, http.StatusOK, nil
 }
 
+func (fu *ForumUsecase) GetForumTags(slug string, actor string, params map[string][]string) (models.TagCounts, int, error) {
+	findedForum, err := fu.ForumRepo.FindForumBySlug(slug)
+	if err != nil {
+		return []models.TagCount{}, http.StatusNotFound, err
+	}
+	code, err := fu.checkReadAccess(actor, findedForum)
+	if err != nil {
+		return []models.TagCount{}, code, err
+	}
+
+	limit := 100
+	if len(params["limit"]) > 0 {
+		limit, err = strconv.Atoi(params["limit"][0])
+		if err != nil {
+			return []models.TagCount{}, http.StatusBadRequest, err
+		}
+	}
+
+	findedTags, err := fu.ForumRepo.GetForumTags(findedForum.Slug, limit)
+	if err != nil {
+		return []models.TagCount{}, http.StatusInternalServerError, err
+	}
+
+	return findedTags, http.StatusOK, nil
+}
+
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	normalizedTags := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !arrutils.StringSliceHas(normalizedTags, tag) {
+			normalizedTags = append(normalizedTags, tag)
+		}
+	}
+	return normalizedTags
+}
+
+// tagParams reads the tags to filter threads by, repeated or comma-separated,
+// and whether threads must have all of them (tag_mode=and, the default) or
+// any (tag_mode=or).
+func tagParams(params map[string][]string) ([]string, bool, error) {
+	var tags []string
+	for _, tag := range params["tag"] {
+		tags = append(tags, strings.Split(tag, ",")...)
+	}
+	tags = normalizeTags(tags)
+	matchAllTags := true
+	if len(params["tag_mode"]) > 0 {
+		switch params["tag_mode"][0] {
+		case "and":
+		case "or":
+			matchAllTags = false
+		default:
+			return nil, false, errors.New("tag_mode must be and or or")
+		}
+	}
+	return tags, matchAllTags, nil
+}
+
+func hasTags(threadTags []string, tags []string, matchAll bool) bool {
+	if len(tags) == 0 {
+		return true
+	}
+
+	for _, tag := range tags {
+		if arrutils.StringSliceHas(threadTags, tag) != matchAll {
+			return !matchAll
+		}
+	}
+	return matchAll
+}
+
+func checkAllowedTags(settings models.ForumSettings, tags []string) (int, error) {
+	if len(settings.AllowedTags) == 0 {
+		return http.StatusOK, nil
+	}
+
+	allowedTags := normalizeTags(settings.AllowedTags)
+	for _, tag := range tags {
+		if !arrutils.StringSliceHas(allowedTags, tag) {
+			return http.StatusBadRequest, errors.New("tag " + tag + " is not allowed in this forum")
+		}
+	}
+	return http.StatusOK, nil
+}
+
+var searchTypes = []string{"thread", "post"}
+
+func (fu *ForumUsecase) Search(actor string, params map[string][]string) (models.SearchResults, int, error) {
+	code, err := fu.checkActor(actor)
+	if err != nil {
+		return []models.SearchResult{}, code, err
+	}
+
+	filter := models.SearchFilter{
+		Limit:         100,
+		AnonymousOnly: actor == "",
+	}
+	if len(params["q"]) > 0 {
+		filter.Query = strings.TrimSpace(params["q"][0])
+	}
+	if filter.Query == "" {
+		return []models.SearchResult{}, http.StatusBadRequest, errors.New("search query is required")
+	}
+
+	if len(params["type"]) > 0 && params["type"][0] != "" {
+		filter.Type = params["type"][0]
+		if !arrutils.StringSliceHas(searchTypes, filter.Type) {
+			return []models.SearchResult{}, http.StatusBadRequest, errors.New("type must be thread or post")
+		}
+	}
+	if len(params["forum"]) > 0 && params["forum"][0] != "" {
+		findedForum, err := fu.ForumRepo.FindForumBySlug(params["forum"][0])
+		if err != nil {
+			return []models.SearchResult{}, http.StatusNotFound, err
+		}
+		code, err := fu.checkReadAccess(actor, findedForum)
+		if err != nil {
+			return []models.SearchResult{}, code, err
+		}
+		filter.Forum = findedForum.Slug
+	}
+	if len(params["author"]) > 0 && params["author"][0] != "" {
+		findedUser, err := fu.ForumRepo.FindUserByNickname(params["author"][0])
+		if err != nil {
+			return []models.SearchResult{}, http.StatusNotFound, err
+		}
+		filter.Author = findedUser.Nickname
+	}
+	if len(params["thread"]) > 0 && params["thread"][0] != "" {
+		threadId, _ := strconv.Atoi(params["thread"][0])
+		findedThread, err := fu.ForumRepo.FindThreadBySlugOrId(int64(threadId), params["thread"][0])
+		if err != nil {
+			return []models.SearchResult{}, http.StatusNotFound, err
+		}
+		filter.Thread = findedThread.Id
+	}
+
+	if len(params["since"]) > 0 && params["since"][0] != "" {
+		filter.Since, err = time.Parse(time.RFC3339, params["since"][0])
+		if err != nil {
+			return []models.SearchResult{}, http.StatusBadRequest, errors.New("since must be an RFC 3339 date")
+		}
+	}
+	if len(params["until"]) > 0 && params["until"][0] != "" {
+		filter.Until, err = time.Parse(time.RFC3339, params["until"][0])
+		if err != nil {
+			return []models.SearchResult{}, http.StatusBadRequest, errors.New("until must be an RFC 3339 date")
+		}
+	}
+	if len(params["limit"]) > 0 {
+		filter.Limit, err = strconv.Atoi(params["limit"][0])
+		if err != nil || filter.Limit < 0 {
+			return []models.SearchResult{}, http.StatusBadRequest, errors.New("limit must be a non-negative number")
+		}
+	}
+	if len(params["offset"]) > 0 {
+		filter.Offset, err = strconv.Atoi(params["offset"][0])
+		if err != nil || filter.Offset < 0 {
+			return []models.SearchResult{}, http.StatusBadRequest, errors.New("offset must be a non-negative number")
+		}
+	}
+
+	findedResults, err := fu.ForumRepo.Search(filter)
+	if err != nil {
+		return []models.SearchResult{}, http.StatusInternalServerError, err
+	}
+
+	return findedResults, http.StatusOK, nil
+}
+
 func (fu *ForumUsecase) CreateUser(userData models.User) (models.Users, int, error) {
 	findedUsers, err := fu.ForumRepo.FindUsersByEmailOrNickname(userData.Email, userData.Nickname)
 	if err != nil || len(findedUsers) != 0 {
@@ -131,6 +608,14 @@ func (fu *ForumUsecase) GetUser(nickname string) (models.User, int, error) {
 		return models.User{}, http.StatusNotFound, err
 	}
 
+	forumReputation, err := fu.ForumRepo.GetUserReputation(findedUser.Id)
+	if err != nil {
+		return models.User{}, http.StatusInternalServerError, err
+	}
+	reputation := totalReputation(forumReputation)
+	findedUser.Reputation = &reputation
+	findedUser.ForumReputation = forumReputation
+
 	return findedUser, http.StatusOK, nil
 }
 
@@ -165,8 +650,43 @@ func (fu *ForumUsecase) CreatesPosts(threadSlugOrId string, postsData []models.P
 	if err != nil {
 		return []models.Post{}, http.StatusNotFound, err
 	}
+	if findedThread.MovedTo != 0 {
+		return []models.Post{}, http.StatusConflict, errors.New("thread was moved to thread #" + strconv.FormatInt(findedThread.MovedTo, 10))
+	}
+
+	findedForum, err := fu.ForumRepo.FindForumBySlug(findedThread.Forum)
+	if err != nil {
+		return []models.Post{}, http.StatusNotFound, err
+	}
+	settings, err := fu.ForumRepo.GetForumSettings(findedForum.Id)
+	if err != nil {
+		return []models.Post{}, http.StatusInternalServerError, err
+	}
+	if settings.ReadOnly || settings.MinAccountAge > 0 || settings.MaxPostLength > 0 {
+		authors := make(map[string]models.User)
+		for _, post := range postsData {
+			author, ok := authors[strings.ToLower(post.Author)]
+			if !ok {
+				author, err = fu.ForumRepo.FindUserByNickname(post.Author)
+				if err != nil {
+					return []models.Post{}, http.StatusNotFound, err
+				}
+				authors[strings.ToLower(post.Author)] = author
+			}
+
+			code, err := checkPostingRules(settings, findedForum, author, post.Message)
+			if err != nil {
+				return []models.Post{}, code, err
+			}
+		}
+	}
+
+	mentions := make([][]string, 0, len(postsData))
+	for _, post := range postsData {
+		mentions = append(mentions, parseMentions(post.Message, post.Author))
+	}
 
-	createdPosts, err := fu.ForumRepo.CreatePosts(postsData, findedThread)
+	createdPosts, err := fu.ForumRepo.CreatePosts(postsData, findedThread, mentions)
 	if err != nil {
 		if err.Error() == "404" {
 			return []models.Post{}, http.StatusNotFound, err
@@ -178,6 +698,24 @@ func (fu *ForumUsecase) CreatesPosts(threadSlugOrId string, postsData []models.P
 	return createdPosts, http.StatusCreated, nil
 }
 
+var mentionRegexp = regexp.MustCompile(`(?:^|[^\w.@])@([\w.]*\w)`)
+
+// parseMentions returns the distinct nicknames mentioned in a message except
+// the author. Nicknames are compared case-insensitively, as in the database.
+func parseMentions(message string, author string) []string {
+	var nicknames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionRegexp.FindAllStringSubmatch(message, -1) {
+		nickname := strings.ToLower(match[1])
+		if seen[nickname] || strings.EqualFold(nickname, author) {
+			continue
+		}
+		seen[nickname] = true
+		nicknames = append(nicknames, match[1])
+	}
+	return nicknames
+}
+
 func (fu *ForumUsecase) VoteThread(threadSlugOrId string, voteData models.Vote) (models.Thread, int, error) {
 	threadId, _ := strconv.Atoi(threadSlugOrId)
 
@@ -191,138 +729,1451 @@ func (fu *ForumUsecase) VoteThread(threadSlugOrId string, voteData models.Vote)
 		return models.Thread{}, http.StatusNotFound, err
 	}
 
-	err = fu.ForumRepo.FindVote(findedUser.Id, findedThread.Id)
-	if err == nil {
-		err = fu.ForumRepo.UpdateVoteThread(findedUser.Id, findedThread.Id, voteData.Voice)
-		if err != nil {
-			fmt.Println(1)
-			return models.Thread{}, http.StatusNotFound, err
-		}
+	// 0 retracts the vote
+	if voteData.Voice < -1 || voteData.Voice > 1 {
+		return models.Thread{}, http.StatusBadRequest, errors.New("voice must be -1, 0 or 1")
+	}
 
-		findedThread, err = fu.ForumRepo.FindThreadBySlugOrId(int64(threadId), threadSlugOrId)
-		if err != nil {
-			return models.Thread{}, http.StatusNotFound, err
-		}
+	err = fu.ForumRepo.VoteThread(findedUser.Id, findedThread.Id, voteData.Voice)
+	if err != nil {
+		return models.Thread{}, http.StatusInternalServerError, err
+	}
 
+	findedThread, err = fu.ForumRepo.FindThreadBySlugOrId(int64(threadId), threadSlugOrId)
+	if err != nil {
+		fmt.Println(err)
+		return models.Thread{}, http.StatusNotFound, err
+	}
+
+	return findedThread, http.StatusOK, nil
+}
+
+// voteListParams reads the pagination of vote lists, the newest votes come
+// first unless desc=false is asked for.
+func voteListParams(params map[string][]string) (string, string, string, string, error) {
+	limit := "100"
+	if len(params["limit"]) > 0 {
+		limit = params["limit"][0]
+	}
+	if _, err := strconv.Atoi(limit); err != nil {
+		return "", "", "", "", errors.New("limit must be a number")
+	}
+	since := ""
+	if len(params["since"]) > 0 {
+		since = params["since"][0]
+		if _, err := strconv.ParseInt(since, 10, 64); err != nil {
+			return "", "", "", "", errors.New("since must be a vote id")
+		}
+	}
+	desc := "desc"
+	comparisonSign := "<"
+	if len(params["desc"]) > 0 && params["desc"][0] == "false" {
+		desc = ""
+		comparisonSign = ">"
+	}
+	return limit, since, desc, comparisonSign, nil
+}
+
+func (fu *ForumUsecase) GetThreadVotes(threadSlugOrId string, actor string, params map[string][]string) (models.ThreadVotes, int, error) {
+	threadId, _ := strconv.Atoi(threadSlugOrId)
+
+	findedThread, err := fu.ForumRepo.FindThreadBySlugOrId(int64(threadId), threadSlugOrId)
+	if err != nil {
+		return models.ThreadVotes{}, http.StatusNotFound, err
+	}
+	findedForum, err := fu.ForumRepo.FindForumBySlug(findedThread.Forum)
+	if err != nil {
+		return models.ThreadVotes{}, http.StatusNotFound, err
+	}
+	code, err := fu.checkReadAccess(actor, findedForum)
+	if err != nil {
+		return models.ThreadVotes{}, code, err
+	}
+
+	limit, since, desc, comparisonSign, err := voteListParams(params)
+	if err != nil {
+		return models.ThreadVotes{}, http.StatusBadRequest, err
+	}
+
+	settings, err := fu.ForumRepo.GetForumSettings(findedForum.Id)
+	if err != nil {
+		return models.ThreadVotes{}, http.StatusInternalServerError, err
+	}
+
+	var threadVotes models.ThreadVotes
+	threadVotes.Up, threadVotes.Down, err = fu.ForumRepo.CountThreadVotes(findedThread.Id)
+	if err != nil {
+		return models.ThreadVotes{}, http.StatusInternalServerError, err
+	}
+
+	// moderators still see who voted in forums with anonymous votes
+	threadVotes.Voters = []models.Voter{}
+	if settings.AnonymousVotes {
+		threadVotes.Anonymous = true
+		if _, err := fu.checkModerator(actor, findedForum); err != nil {
+			return threadVotes, http.StatusOK, nil
+		}
+	}
+
+	threadVotes.Voters, err = fu.ForumRepo.GetThreadVoters(findedThread.Id, limit, since, desc, comparisonSign)
+	if err != nil {
+		return models.ThreadVotes{}, http.StatusInternalServerError, err
+	}
+
+	return threadVotes, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) GetUserVotes(nickname string, actor string, params map[string][]string) (models.UserVotes, int, error) {
+	findedUser, code, err := fu.checkOwner(actor, nickname)
+	if err != nil {
+		return []models.UserVote{}, code, err
+	}
+
+	limit, since, desc, comparisonSign, err := voteListParams(params)
+	if err != nil {
+		return []models.UserVote{}, http.StatusBadRequest, err
+	}
+
+	findedVotes, err := fu.ForumRepo.GetUserVotes(findedUser.Id, limit, since, desc, comparisonSign)
+	if err != nil {
+		return []models.UserVote{}, http.StatusInternalServerError, err
+	}
+
+	return findedVotes, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) VotePost(id string, actor string, voteData models.Vote) (models.Post, int, error) {
+	if actor == "" {
+		return models.Post{}, http.StatusUnauthorized, errors.New("authentication required")
+	}
+	findedPost, findedUser, code, err := fu.findPostReactor(id, actor)
+	if err != nil {
+		return models.Post{}, code, err
+	}
+
+	// 0 retracts the vote
+	if voteData.Voice < -1 || voteData.Voice > 1 {
+		return models.Post{}, http.StatusBadRequest, errors.New("voice must be -1, 0 or 1")
+	}
+
+	err = fu.ForumRepo.VotePost(findedUser.Id, findedPost.Id, voteData.Voice)
+	if err != nil {
+		return models.Post{}, http.StatusInternalServerError, err
+	}
+
+	return fu.findPostWithCounts(findedPost.Id)
+}
+
+// findPostReactor finds the post and the user reacting to it, who must be
+// able to read its forum.
+func (fu *ForumUsecase) findPostReactor(id string, actor string) (models.Post, models.User, int, error) {
+	postId, _ := strconv.ParseInt(id, 10, 64)
+
+	findedPost, err := fu.ForumRepo.FindPost(postId)
+	if err != nil {
+		return models.Post{}, models.User{}, http.StatusNotFound, err
+	}
+	findedForum, err := fu.ForumRepo.FindForumBySlug(findedPost.Forum)
+	if err != nil {
+		return models.Post{}, models.User{}, http.StatusNotFound, err
+	}
+	code, err := fu.checkReadAccess(actor, findedForum)
+	if err != nil {
+		return models.Post{}, models.User{}, code, err
+	}
+
+	if actor == "" {
+		return findedPost, models.User{}, http.StatusOK, nil
+	}
+	findedUser, err := fu.ForumRepo.FindUserByNickname(actor)
+	if err != nil {
+		return models.Post{}, models.User{}, http.StatusUnauthorized, errors.New("Can't find user by nickname " + actor)
+	}
+	return findedPost, findedUser, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) AddPostReaction(id string, emoji string, actor string) (models.Post, int, error) {
+	if actor == "" {
+		return models.Post{}, http.StatusUnauthorized, errors.New("authentication required")
+	}
+	findedPost, findedUser, code, err := fu.findPostReactor(id, actor)
+	if err != nil {
+		return models.Post{}, code, err
+	}
+
+	if !arrutils.StringSliceHas(fu.reactions, emoji) {
+		return models.Post{}, http.StatusBadRequest, errors.New("reaction " + emoji + " is not allowed")
+	}
+
+	err = fu.ForumRepo.AddPostReaction(findedUser.Id, findedPost.Id, emoji)
+	if err != nil {
+		return models.Post{}, http.StatusInternalServerError, err
+	}
+
+	return fu.findPostWithCounts(findedPost.Id)
+}
+
+func (fu *ForumUsecase) DeletePostReaction(id string, emoji string, actor string) (models.Post, int, error) {
+	if actor == "" {
+		return models.Post{}, http.StatusUnauthorized, errors.New("authentication required")
+	}
+	findedPost, findedUser, code, err := fu.findPostReactor(id, actor)
+	if err != nil {
+		return models.Post{}, code, err
+	}
+
+	err = fu.ForumRepo.DeletePostReaction(findedUser.Id, findedPost.Id, emoji)
+	if err != nil {
+		return models.Post{}, http.StatusInternalServerError, err
+	}
+
+	return fu.findPostWithCounts(findedPost.Id)
+}
+
+func (fu *ForumUsecase) GetPostReactions(id string, actor string) (models.ReactionGroups, int, error) {
+	findedPost, _, code, err := fu.findPostReactor(id, actor)
+	if err != nil {
+		return []models.ReactionGroup{}, code, err
+	}
+
+	findedReactions, err := fu.ForumRepo.GetPostReactions(findedPost.Id)
+	if err != nil {
+		return []models.ReactionGroup{}, http.StatusInternalServerError, err
+	}
+
+	return findedReactions, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) findPostWithCounts(postId int64) (models.Post, int, error) {
+	findedPost, err := fu.ForumRepo.FindPost(postId)
+	if err != nil {
+		return models.Post{}, http.StatusNotFound, err
+	}
+	return findedPost, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) FindThreadBySlugOrId(threadSlugOrId string, actor string) (models.Thread, int, error) {
+	threadId, _ := strconv.Atoi(threadSlugOrId)
+
+	findedThread, err := fu.ForumRepo.FindThreadBySlugOrId(int64(threadId), threadSlugOrId)
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+
+	findedForum, err := fu.ForumRepo.FindForumBySlug(findedThread.Forum)
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+	code, err := fu.checkReadAccess(actor, findedForum)
+	if err != nil {
+		return models.Thread{}, code, err
+	}
+
+	if actor != "" {
+		subscribed, err := fu.ForumRepo.IsThreadSubscribed(actor, findedThread.Id)
+		if err != nil {
+			return models.Thread{}, http.StatusInternalServerError, err
+		}
+		findedThread.Subscribed = &subscribed
+	}
+
+	return findedThread, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) GetPosts(threadSlugOrId string, actor string, params map[string][]string) (models.Posts, int, error) {
+	threadId, _ := strconv.Atoi(threadSlugOrId)
+
+	findedThread, err := fu.ForumRepo.FindThreadBySlugOrId(int64(threadId), threadSlugOrId)
+	if err != nil {
+		return []models.Post{}, http.StatusNotFound, err
+	}
+
+	findedForum, err := fu.ForumRepo.FindForumBySlug(findedThread.Forum)
+	if err != nil {
+		return []models.Post{}, http.StatusNotFound, err
+	}
+	code, err := fu.checkReadAccess(actor, findedForum)
+	if err != nil {
+		return []models.Post{}, code, err
+	}
+
+	limit := "100"
+	if len(params["limit"]) > 0 {
+		limit = params["limit"][0]
+	}
+	since := ""
+	if len(params["since"]) > 0 {
+		since = params["since"][0]
+	}
+	sort := "flat"
+	if len(params["sort"]) > 0 {
+		sort = params["sort"][0]
+	}
+	desc := ""
+	comparisonSign := ">"
+	if len(params["desc"]) > 0 && params["desc"][0] == "true" {
+		desc = "desc"
+		comparisonSign = "<"
+	}
+
+	var reader models.User
+	var lastPostId int64
+	var readPostIds []int64
+	if actor != "" {
+		reader, err = fu.ForumRepo.FindUserByNickname(actor)
+		if err != nil {
+			return []models.Post{}, http.StatusUnauthorized, err
+		}
+		lastPostId, readPostIds, err = fu.ForumRepo.GetThreadRead(reader.Id, findedThread.Id)
+		if err != nil {
+			return []models.Post{}, http.StatusInternalServerError, err
+		}
+	}
+
+	// unread=true starts the page at the first unread post, in reading order
+	var firstUnreadId int64
+	if len(params["unread"]) > 0 && params["unread"][0] == "true" {
+		if reader.Id == 0 {
+			return []models.Post{}, http.StatusUnauthorized, errors.New("authentication required")
+		}
+		firstUnreadId, err = fu.ForumRepo.FindFirstUnreadPost(reader.Id, findedThread.Id, lastPostId, sort)
+		if err != nil {
+			return []models.Post{}, http.StatusInternalServerError, err
+		}
+		if firstUnreadId == 0 {
+			return []models.Post{}, http.StatusOK, nil
+		}
+	}
+
+	findedPosts, err := fu.ForumRepo.GetPosts(findedThread.Id, limit, since, sort, desc, comparisonSign, firstUnreadId)
+	if err != nil {
+		return []models.Post{}, http.StatusNotFound, err
+	}
+
+	if reader.Id != 0 && len(findedPosts) > 0 {
+		for _, post := range findedPosts {
+			readPostIds = append(readPostIds, post.Id)
+		}
+		firstUnreadId, err := fu.ForumRepo.FindFirstUnlistedPost(findedThread.Id, lastPostId, readPostIds)
+		if err != nil {
+			return []models.Post{}, http.StatusInternalServerError, err
+		}
+		marker, readPastMarker := readMarker(lastPostId, readPostIds, firstUnreadId)
+		_, err = fu.ForumRepo.MarkThreadRead(reader.Id, findedThread.Id, marker, readPastMarker)
+		if err != nil {
+			return []models.Post{}, http.StatusInternalServerError, err
+		}
+	}
+
+	return findedPosts, http.StatusOK, nil
+}
+
+// readMarker moves the read marker of a thread over the read posts that follow
+// it without a gap, up to firstUnreadId, the first post after the marker that
+// is not read, or 0 if there is none. The posts read past the gap, which pages
+// of the tree sorts or in desc order leave, are returned to be kept until the
+// marker reaches them.
+func readMarker(lastPostId int64, readPostIds []int64, firstUnreadId int64) (int64, []int64) {
+	marker := lastPostId
+	for _, postId := range readPostIds {
+		if postId > marker && (firstUnreadId == 0 || postId < firstUnreadId) {
+			marker = postId
+		}
+	}
+
+	readPastMarker := make([]int64, 0)
+	for _, postId := range readPostIds {
+		if postId > marker && !arrutils.Int64SliceHas(readPastMarker, postId) {
+			readPastMarker = append(readPastMarker, postId)
+		}
+	}
+	return marker, readPastMarker
+}
+
+func (fu *ForumUsecase) UpdateThread(threadSlugOrId string, newThread models.Thread) (models.Thread, int, error) {
+	threadId, _ := strconv.Atoi(threadSlugOrId)
+
+	findedThread, err := fu.ForumRepo.FindThreadBySlugOrId(int64(threadId), threadSlugOrId)
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+
+	if len(newThread.Title) == 0 && len(newThread.Message) == 0 && newThread.Tags == nil {
 		return findedThread, http.StatusOK, nil
 	}
-	err = fu.ForumRepo.AddVoteThread(findedUser.Id, findedThread.Id, voteData.Voice)
+
+	if len(newThread.Title) == 0 {
+		newThread.Title = findedThread.Title
+	}
+	if len(newThread.Message) == 0 {
+		newThread.Message = findedThread.Message
+	}
+	if newThread.Tags == nil {
+		newThread.Tags = findedThread.Tags
+	} else {
+		findedForum, err := fu.ForumRepo.FindForumBySlug(findedThread.Forum)
+		if err != nil {
+			return models.Thread{}, http.StatusNotFound, err
+		}
+		settings, err := fu.ForumRepo.GetForumSettings(findedForum.Id)
+		if err != nil {
+			return models.Thread{}, http.StatusInternalServerError, err
+		}
+
+		newThread.Tags = normalizeTags(newThread.Tags)
+		code, err := checkAllowedTags(settings, newThread.Tags)
+		if err != nil {
+			return models.Thread{}, code, err
+		}
+	}
+
+	updatedThread, err := fu.ForumRepo.UpdateThread(findedThread.Id, newThread)
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+
+	return updatedThread, http.StatusOK, nil
+}
+
+// PinThread lets moderators pin threads of their forum, while announcements
+// show up in every forum and are left to admins.
+// Pinned threads and announcements all head the first page of a forum, so
+// their number is capped when they are pinned.
+const (
+	maxPinnedThreads = 10
+	maxAnnouncements = 10
+)
+
+func (fu *ForumUsecase) PinThread(threadSlugOrId string, actor string, pinData models.ThreadPin) (models.Thread, int, error) {
+	threadId, _ := strconv.Atoi(threadSlugOrId)
+
+	findedThread, err := fu.ForumRepo.FindThreadBySlugOrId(int64(threadId), threadSlugOrId)
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+
+	if pinData.Announcement || findedThread.Announcement {
+		code, err := fu.checkAdmin(actor)
+		if err != nil {
+			return models.Thread{}, code, err
+		}
+	} else {
+		findedForum, err := fu.ForumRepo.FindForumBySlug(findedThread.Forum)
+		if err != nil {
+			return models.Thread{}, http.StatusNotFound, err
+		}
+		code, err := fu.checkModerator(actor, findedForum)
+		if err != nil {
+			return models.Thread{}, code, err
+		}
+	}
+
+	if !pinData.Pinned {
+		pinData.Order = 0
+	}
+
+	pinnedCount, announcementCount, err := fu.ForumRepo.CountPinnedThreads(findedThread.Forum)
+	if err != nil {
+		return models.Thread{}, http.StatusInternalServerError, err
+	}
+	if pinData.Pinned && !findedThread.Pinned && pinnedCount >= maxPinnedThreads {
+		return models.Thread{}, http.StatusConflict, errors.New("Forum " + findedThread.Forum + " already has " + strconv.Itoa(maxPinnedThreads) + " pinned threads")
+	}
+	if pinData.Announcement && !findedThread.Announcement && announcementCount >= maxAnnouncements {
+		return models.Thread{}, http.StatusConflict, errors.New("There are already " + strconv.Itoa(maxAnnouncements) + " announcements")
+	}
+
+	pinnedThread, err := fu.ForumRepo.PinThread(findedThread.Id, pinData)
+	if err != nil {
+		return models.Thread{}, http.StatusInternalServerError, err
+	}
+
+	return pinnedThread, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) checkAdmin(actor string) (int, error) {
+	if actor == "" {
+		return http.StatusUnauthorized, errors.New("authentication required")
+	}
+
+	findedUser, err := fu.ForumRepo.FindUserByNickname(actor)
+	if err != nil {
+		return http.StatusUnauthorized, err
+	}
+
+	if !findedUser.IsAdmin {
+		return http.StatusForbidden, errors.New("admin rights required")
+	}
+
+	return http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) checkModerator(actor string, forum models.Forum) (int, error) {
+	if actor == "" {
+		return http.StatusUnauthorized, errors.New("authentication required")
+	}
+
+	findedUser, err := fu.ForumRepo.FindUserByNickname(actor)
+	if err != nil {
+		return http.StatusUnauthorized, err
+	}
+
+	if !findedUser.IsAdmin && !strings.EqualFold(findedUser.Nickname, forum.User) {
+		return http.StatusForbidden, errors.New("Can't moderate forum " + forum.Slug)
+	}
+
+	return http.StatusOK, nil
+}
+
+// checkActor rejects the nickname sent by a client unless it belongs to a
+// user, an empty one stands for an anonymous client.
+func (fu *ForumUsecase) checkActor(actor string) (int, error) {
+	if actor == "" {
+		return http.StatusOK, nil
+	}
+
+	_, err := fu.ForumRepo.FindUserByNickname(actor)
+	if err != nil {
+		return http.StatusUnauthorized, errors.New("Can't find user by nickname " + actor)
+	}
+
+	return http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) checkReadAccess(actor string, forum models.Forum) (int, error) {
+	code, err := fu.checkActor(actor)
+	if err != nil {
+		return code, err
+	}
+
+	settings, err := fu.ForumRepo.GetForumSettings(forum.Id)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if !settings.AnonymousRead && actor == "" {
+		return http.StatusUnauthorized, errors.New("Forum " + forum.Slug + " can't be read anonymously")
+	}
+
+	return http.StatusOK, nil
+}
+
+// checkPostingRules applies the forum settings to a new thread or post.
+// The forum owner and admins are not restricted by them.
+func checkPostingRules(settings models.ForumSettings, forum models.Forum, author models.User, message string) (int, error) {
+	if author.IsAdmin || strings.EqualFold(author.Nickname, forum.User) {
+		return http.StatusOK, nil
+	}
+
+	if settings.ReadOnly {
+		return http.StatusForbidden, errors.New("Forum " + forum.Slug + " is read-only")
+	}
+	if settings.MinAccountAge > 0 && time.Since(author.Created) < time.Duration(settings.MinAccountAge)*time.Second {
+		return http.StatusForbidden, errors.New("Account of " + author.Nickname + " is too new to post in forum " + forum.Slug)
+	}
+	if settings.MaxPostLength > 0 && utf8.RuneCountInString(message) > int(settings.MaxPostLength) {
+		return http.StatusBadRequest, errors.New("Message is longer than " + strconv.Itoa(int(settings.MaxPostLength)) + " characters")
+	}
+
+	return http.StatusOK, nil
+}
+
+// checkThreadReputation requires the minimum reputation of the forum, earned
+// in that forum, to create threads. Like the posting rules it doesn't apply to
+// the forum owner and admins.
+func (fu *ForumUsecase) checkThreadReputation(settings models.ForumSettings, forum models.Forum, author models.User) (int, error) {
+	if settings.MinReputation <= 0 || author.IsAdmin || strings.EqualFold(author.Nickname, forum.User) {
+		return http.StatusOK, nil
+	}
+
+	forumReputation, err := fu.ForumRepo.GetUserReputation(author.Id)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if reputationIn(forumReputation, forum.Slug) < settings.MinReputation {
+		return http.StatusForbidden, errors.New("Reputation of " + author.Nickname + " is too low to create threads in forum " + forum.Slug)
+	}
+
+	return http.StatusOK, nil
+}
+
+func totalReputation(forumReputation []models.ForumReputation) int32 {
+	var total int32
+	for _, reputation := range forumReputation {
+		total += reputation.Reputation
+	}
+	return total
+}
+
+func reputationIn(forumReputation []models.ForumReputation, slug string) int32 {
+	for _, reputation := range forumReputation {
+		if strings.EqualFold(reputation.Forum, slug) {
+			return reputation.Reputation
+		}
+	}
+	return 0
+}
+
+func (fu *ForumUsecase) MoveThread(threadSlugOrId string, actor string, moveData models.ThreadMove) (models.Thread, int, error) {
+	threadId, _ := strconv.Atoi(threadSlugOrId)
+
+	findedThread, err := fu.ForumRepo.FindThreadBySlugOrId(int64(threadId), threadSlugOrId)
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+	if findedThread.MovedTo != 0 {
+		return models.Thread{}, http.StatusConflict, errors.New("thread was already moved")
+	}
+
+	fromForum, err := fu.ForumRepo.FindForumBySlug(findedThread.Forum)
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+
+	code, err := fu.checkModerator(actor, fromForum)
+	if err != nil {
+		return models.Thread{}, code, err
+	}
+
+	toForum, err := fu.ForumRepo.FindForumBySlug(moveData.Forum)
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+	if toForum.Id == fromForum.Id {
+		return models.Thread{}, http.StatusConflict, errors.New("thread is already in forum " + toForum.Slug)
+	}
+
+	movedThread, err := fu.ForumRepo.MoveThread(findedThread, fromForum, toForum, moveData.Redirect)
+	if err != nil {
+		return models.Thread{}, http.StatusInternalServerError, err
+	}
+
+	return movedThread, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) MergeThreads(threadSlugOrId string, actor string, mergeData models.ThreadMerge) (models.Thread, int, error) {
+	threadId, _ := strconv.Atoi(threadSlugOrId)
+	sourceThread, err := fu.ForumRepo.FindThreadBySlugOrId(int64(threadId), threadSlugOrId)
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+
+	targetId, _ := strconv.Atoi(mergeData.Target)
+	targetThread, err := fu.ForumRepo.FindThreadBySlugOrId(int64(targetId), mergeData.Target)
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+
+	if sourceThread.Id == targetThread.Id {
+		return models.Thread{}, http.StatusConflict, errors.New("can't merge thread into itself")
+	}
+	if sourceThread.MovedTo != 0 || targetThread.MovedTo != 0 {
+		return models.Thread{}, http.StatusConflict, errors.New("can't merge moved threads")
+	}
+
+	sourceForum, err := fu.ForumRepo.FindForumBySlug(sourceThread.Forum)
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+	targetForum, err := fu.ForumRepo.FindForumBySlug(targetThread.Forum)
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+
+	code, err := fu.checkModerator(actor, sourceForum)
+	if err != nil {
+		return models.Thread{}, code, err
+	}
+	code, err = fu.checkModerator(actor, targetForum)
+	if err != nil {
+		return models.Thread{}, code, err
+	}
+
+	mergedThread, err := fu.ForumRepo.MergeThreads(sourceThread, targetThread, sourceForum, targetForum)
+	if err != nil {
+		return models.Thread{}, http.StatusInternalServerError, err
+	}
+
+	return mergedThread, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) SplitThread(id string, actor string, splitData models.ThreadSplit) (models.Thread, int, error) {
+	postId, _ := strconv.Atoi(id)
+
+	findedPost, err := fu.ForumRepo.FindPost(int64(postId))
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+
+	findedForum, err := fu.ForumRepo.FindForumBySlug(findedPost.Forum)
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+
+	code, err := fu.checkModerator(actor, findedForum)
+	if err != nil {
+		return models.Thread{}, code, err
+	}
+
+	if len(splitData.Title) == 0 {
+		return models.Thread{}, http.StatusBadRequest, errors.New("title is required")
+	}
+	if splitData.Slug != "" {
+		findedThread, err := fu.ForumRepo.FindThreadBySlug(splitData.Slug)
+		if err == nil {
+			return findedThread, http.StatusConflict, errors.New("thread with slug " + splitData.Slug + " already exists")
+		}
+	}
+
+	createdThread, err := fu.ForumRepo.SplitThread(findedPost, models.Thread{
+		Title: splitData.Title,
+		Slug:  splitData.Slug,
+	})
+	if err != nil {
+		return models.Thread{}, http.StatusInternalServerError, err
+	}
+
+	return createdThread, http.StatusCreated, nil
+}
+
+func (fu *ForumUsecase) GetForumUsers(forumSlug string, actor string, params map[string][]string) (models.Users, int, error) {
+	findedForum, err := fu.ForumRepo.FindForumBySlug(forumSlug)
+	if err != nil {
+		return []models.User{}, http.StatusNotFound, err
+	}
+	code, err := fu.checkReadAccess(actor, findedForum)
+	if err != nil {
+		return []models.User{}, code, err
+	}
+
+	limit := "100"
+	if len(params["limit"]) > 0 {
+		limit = params["limit"][0]
+	}
+	since := ""
+	if len(params["since"]) > 0 {
+		since = params["since"][0]
+	}
+	desc := ""
+	comparisonSign := ">"
+	if len(params["desc"]) > 0 && params["desc"][0] == "true" {
+		desc = "desc"
+		comparisonSign = "<"
+	}
+
+	findedUsers, err := fu.ForumRepo.GetForumUsers(findedForum.Id, limit, since, desc, comparisonSign)
+	if err != nil {
+		return []models.User{}, http.StatusNotFound, err
+	}
+
+	return findedUsers, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) SearchUsers(actor string, params map[string][]string) (models.Users, int, error) {
+	code, err := fu.checkActor(actor)
+	if err != nil {
+		return []models.User{}, code, err
+	}
+
+	query := ""
+	if len(params["query"]) > 0 {
+		query = strings.TrimSpace(params["query"][0])
+	}
+	if query == "" {
+		return []models.User{}, http.StatusBadRequest, errors.New("query is required")
+	}
+
+	var forumId int64
+	if len(params["forum"]) > 0 && params["forum"][0] != "" {
+		findedForum, err := fu.ForumRepo.FindForumBySlug(params["forum"][0])
+		if err != nil {
+			return []models.User{}, http.StatusNotFound, err
+		}
+		// the members of a forum are listed to its readers only
+		code, err := fu.checkReadAccess(actor, findedForum)
+		if err != nil {
+			return []models.User{}, code, err
+		}
+		forumId = findedForum.Id
+	}
+
+	limit := 10
+	if len(params["limit"]) > 0 {
+		limit, err = strconv.Atoi(params["limit"][0])
+		if err != nil || limit < 0 {
+			return []models.User{}, http.StatusBadRequest, errors.New("limit must be a non-negative number")
+		}
+	}
+	offset := 0
+	if len(params["offset"]) > 0 {
+		offset, err = strconv.Atoi(params["offset"][0])
+		if err != nil || offset < 0 {
+			return []models.User{}, http.StatusBadRequest, errors.New("offset must be a non-negative number")
+		}
+	}
+
+	findedUsers, err := fu.ForumRepo.SearchUsers(query, forumId, limit, offset)
+	if err != nil {
+		return []models.User{}, http.StatusInternalServerError, err
+	}
+
+	return findedUsers, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) GetUserMentions(nickname string, actor string, params map[string][]string) (models.Posts, int, error) {
+	findedUser, err := fu.ForumRepo.FindUserByNickname(nickname)
+	if err != nil {
+		return []models.Post{}, http.StatusNotFound, err
+	}
+	code, err := fu.checkActor(actor)
+	if err != nil {
+		return []models.Post{}, code, err
+	}
+
+	limit := "100"
+	if len(params["limit"]) > 0 {
+		limit = params["limit"][0]
+	}
+	if _, err := strconv.Atoi(limit); err != nil {
+		return []models.Post{}, http.StatusBadRequest, errors.New("limit must be a number")
+	}
+	since := ""
+	if len(params["since"]) > 0 {
+		since = params["since"][0]
+		if _, err := strconv.ParseInt(since, 10, 64); err != nil {
+			return []models.Post{}, http.StatusBadRequest, errors.New("since must be a post id")
+		}
+	}
+	// the newest mentions come first unless desc=false is asked for
+	desc := "desc"
+	comparisonSign := "<"
+	if len(params["desc"]) > 0 && params["desc"][0] == "false" {
+		desc = ""
+		comparisonSign = ">"
+	}
+
+	findedPosts, err := fu.ForumRepo.GetUserMentions(findedUser.Id, limit, since, desc, comparisonSign, actor == "")
+	if err != nil {
+		return []models.Post{}, http.StatusInternalServerError, err
+	}
+
+	return findedPosts, http.StatusOK, nil
+}
+
+// checkOwner lets users access only their own inbox and feed.
+func (fu *ForumUsecase) checkOwner(actor string, nickname string) (models.User, int, error) {
+	findedUser, err := fu.ForumRepo.FindUserByNickname(nickname)
+	if err != nil {
+		return models.User{}, http.StatusNotFound, err
+	}
+
+	if actor == "" {
+		return models.User{}, http.StatusUnauthorized, errors.New("authentication required")
+	}
+	if !strings.EqualFold(actor, findedUser.Nickname) {
+		return models.User{}, http.StatusForbidden, errors.New("Can't access private data of " + findedUser.Nickname)
+	}
+
+	return findedUser, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) GetNotifications(nickname string, actor string, params map[string][]string) (models.Notifications, int, error) {
+	findedUser, code, err := fu.checkOwner(actor, nickname)
+	if err != nil {
+		return []models.Notification{}, code, err
+	}
+
+	limit := "100"
+	if len(params["limit"]) > 0 {
+		limit = params["limit"][0]
+	}
+	if _, err := strconv.Atoi(limit); err != nil {
+		return []models.Notification{}, http.StatusBadRequest, errors.New("limit must be a number")
+	}
+	since := ""
+	if len(params["since"]) > 0 {
+		since = params["since"][0]
+		if _, err := strconv.ParseInt(since, 10, 64); err != nil {
+			return []models.Notification{}, http.StatusBadRequest, errors.New("since must be a notification id")
+		}
+	}
+	unreadOnly := len(params["unread"]) > 0 && params["unread"][0] == "true"
+
+	findedNotifications, err := fu.ForumRepo.GetNotifications(findedUser.Id, limit, since, unreadOnly)
+	if err != nil {
+		return []models.Notification{}, http.StatusInternalServerError, err
+	}
+
+	return findedNotifications, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) CountNotifications(nickname string, actor string) (models.NotificationCount, int, error) {
+	findedUser, code, err := fu.checkOwner(actor, nickname)
+	if err != nil {
+		return models.NotificationCount{}, code, err
+	}
+
+	unread, err := fu.ForumRepo.CountUnreadNotifications(findedUser.Id)
+	if err != nil {
+		return models.NotificationCount{}, http.StatusInternalServerError, err
+	}
+
+	return models.NotificationCount{Unread: unread}, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) MarkNotificationsRead(nickname string, actor string, read models.NotificationsRead) (models.NotificationCount, int, error) {
+	findedUser, code, err := fu.checkOwner(actor, nickname)
+	if err != nil {
+		return models.NotificationCount{}, code, err
+	}
+
+	err = fu.ForumRepo.MarkNotificationsRead(findedUser.Id, read.Ids)
+	if err != nil {
+		return models.NotificationCount{}, http.StatusInternalServerError, err
+	}
+
+	unread, err := fu.ForumRepo.CountUnreadNotifications(findedUser.Id)
+	if err != nil {
+		return models.NotificationCount{}, http.StatusInternalServerError, err
+	}
+
+	return models.NotificationCount{Unread: unread}, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) GetNotificationPreferences(nickname string, actor string) (models.NotificationPreferences, int, error) {
+	findedUser, code, err := fu.checkOwner(actor, nickname)
+	if err != nil {
+		return models.NotificationPreferences{}, code, err
+	}
+
+	findedPreferences, err := fu.ForumRepo.GetNotificationPreferences(findedUser.Id)
+	if err != nil {
+		return models.NotificationPreferences{}, http.StatusInternalServerError, err
+	}
+
+	return findedPreferences, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) UpdateNotificationPreferences(nickname string, actor string, preferences models.NotificationPreferences) (models.NotificationPreferences, int, error) {
+	findedUser, code, err := fu.checkOwner(actor, nickname)
+	if err != nil {
+		return models.NotificationPreferences{}, code, err
+	}
+
+	err = fu.ForumRepo.UpdateNotificationPreferences(findedUser.Id, preferences)
+	if err != nil {
+		return models.NotificationPreferences{}, http.StatusInternalServerError, err
+	}
+
+	return preferences, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) SubscribeThread(threadSlugOrId string, actor string, subscribe bool) (models.Thread, int, error) {
+	if actor == "" {
+		return models.Thread{}, http.StatusUnauthorized, errors.New("authentication required")
+	}
+	findedUser, err := fu.ForumRepo.FindUserByNickname(actor)
+	if err != nil {
+		return models.Thread{}, http.StatusUnauthorized, err
+	}
+
+	threadId, _ := strconv.Atoi(threadSlugOrId)
+	findedThread, err := fu.ForumRepo.FindThreadBySlugOrId(int64(threadId), threadSlugOrId)
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+
+	// unsubscribing is always allowed, subscribing needs read access
+	if subscribe {
+		var findedForum models.Forum
+		findedForum, err = fu.ForumRepo.FindForumBySlug(findedThread.Forum)
+		if err != nil {
+			return models.Thread{}, http.StatusNotFound, err
+		}
+		if code, err := fu.checkReadAccess(actor, findedForum); err != nil {
+			return models.Thread{}, code, err
+		}
+		err = fu.ForumRepo.SubscribeThread([]string{findedUser.Nickname}, findedThread.Id)
+	} else {
+		err = fu.ForumRepo.UnsubscribeThread(findedUser.Id, findedThread.Id)
+	}
 	if err != nil {
-		return models.Thread{}, http.StatusNotFound, err
+		return models.Thread{}, http.StatusInternalServerError, err
 	}
 
-	findedThread, err = fu.ForumRepo.FindThreadBySlugOrId(int64(threadId), threadSlugOrId)
+	findedThread.Subscribed = &subscribe
+	return findedThread, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) SubscribeForum(slug string, actor string, subscribe bool) (int, error) {
+	if actor == "" {
+		return http.StatusUnauthorized, errors.New("authentication required")
+	}
+	findedUser, err := fu.ForumRepo.FindUserByNickname(actor)
 	if err != nil {
-		fmt.Println(err)
-		return models.Thread{}, http.StatusNotFound, err
+		return http.StatusUnauthorized, err
 	}
 
-	return findedThread, http.StatusOK, nil
+	findedForum, err := fu.ForumRepo.FindForumBySlug(slug)
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+
+	// unsubscribing is always allowed, subscribing needs read access
+	if subscribe {
+		if code, err := fu.checkReadAccess(actor, findedForum); err != nil {
+			return code, err
+		}
+		err = fu.ForumRepo.SubscribeForum(findedUser.Id, findedForum.Id)
+	} else {
+		err = fu.ForumRepo.UnsubscribeForum(findedUser.Id, findedForum.Id)
+	}
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
 }
 
-func (fu *ForumUsecase) FindThreadBySlugOrId(threadSlugOrId string) (models.Thread, int, error) {
-	threadId, _ := strconv.Atoi(threadSlugOrId)
+// GetFeed returns the posts in watched threads and forums written since the
+// last visit and moves the visit cursor past them, unless peek=true is given.
+func (fu *ForumUsecase) GetFeed(nickname string, actor string, params map[string][]string) (models.Posts, int, error) {
+	findedUser, code, err := fu.checkOwner(actor, nickname)
+	if err != nil {
+		return []models.Post{}, code, err
+	}
+
+	limit := 100
+	if len(params["limit"]) > 0 {
+		limit, err = strconv.Atoi(params["limit"][0])
+		if err != nil || limit < 0 {
+			return []models.Post{}, http.StatusBadRequest, errors.New("limit must be a non-negative number")
+		}
+	}
+	peek := len(params["peek"]) > 0 && params["peek"][0] == "true"
+
+	lastPostId, err := fu.ForumRepo.GetFeedCursor(findedUser.Id)
+	if err != nil {
+		return []models.Post{}, http.StatusInternalServerError, err
+	}
 
+	findedPosts, err := fu.ForumRepo.GetFeed(findedUser, lastPostId, limit)
+	if err != nil {
+		return []models.Post{}, http.StatusInternalServerError, err
+	}
+
+	if !peek && len(findedPosts) > 0 {
+		err = fu.ForumRepo.UpdateFeedCursor(findedUser.Id, findedPosts[len(findedPosts)-1].Id)
+		if err != nil {
+			return []models.Post{}, http.StatusInternalServerError, err
+		}
+	}
+
+	return findedPosts, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) MarkThreadRead(threadSlugOrId string, actor string, readData models.ThreadRead) (models.ThreadRead, int, error) {
+	if actor == "" {
+		return models.ThreadRead{}, http.StatusUnauthorized, errors.New("authentication required")
+	}
+	reader, err := fu.ForumRepo.FindUserByNickname(actor)
+	if err != nil {
+		return models.ThreadRead{}, http.StatusUnauthorized, err
+	}
+
+	threadId, _ := strconv.Atoi(threadSlugOrId)
 	findedThread, err := fu.ForumRepo.FindThreadBySlugOrId(int64(threadId), threadSlugOrId)
 	if err != nil {
-		return models.Thread{}, http.StatusNotFound, err
+		return models.ThreadRead{}, http.StatusNotFound, err
 	}
 
-	return findedThread, http.StatusOK, nil
+	// without a post the whole thread is marked as read
+	lastPostId := findedThread.LastPostId
+	if readData.Post != 0 {
+		findedPost, err := fu.ForumRepo.FindPost(readData.Post)
+		if err != nil {
+			return models.ThreadRead{}, http.StatusNotFound, err
+		}
+		if int64(findedPost.Thread) != findedThread.Id {
+			return models.ThreadRead{}, http.StatusConflict, errors.New("post was created in another thread")
+		}
+		lastPostId = findedPost.Id
+	}
+
+	markedPostId, err := fu.ForumRepo.MarkThreadRead(reader.Id, findedThread.Id, lastPostId, nil)
+	if err != nil {
+		return models.ThreadRead{}, http.StatusInternalServerError, err
+	}
+
+	unreadPosts, err := fu.ForumRepo.CountUnreadPosts(reader.Id, []int64{findedThread.Id})
+	if err != nil {
+		return models.ThreadRead{}, http.StatusInternalServerError, err
+	}
+
+	return models.ThreadRead{Post: markedPostId, Unread: unreadPosts[findedThread.Id]}, http.StatusOK, nil
 }
 
-func (fu *ForumUsecase) GetPosts(threadSlugOrId string, params map[string][]string) (models.Posts, int, error) {
+func (fu *ForumUsecase) StreamThreadEvents(threadSlugOrId string, actor string, lastEventId int64) (models.EventStream, int, error) {
 	threadId, _ := strconv.Atoi(threadSlugOrId)
 
 	findedThread, err := fu.ForumRepo.FindThreadBySlugOrId(int64(threadId), threadSlugOrId)
 	if err != nil {
-		return []models.Post{}, http.StatusNotFound, err
+		return models.EventStream{}, http.StatusNotFound, err
 	}
 
-	limit := "100"
-	if len(params["limit"]) > 0 {
-		limit = params["limit"][0]
+	findedForum, err := fu.ForumRepo.FindForumBySlug(findedThread.Forum)
+	if err != nil {
+		return models.EventStream{}, http.StatusNotFound, err
 	}
-	since := ""
-	if len(params["since"]) > 0 {
-		since = params["since"][0]
+	code, err := fu.checkReadAccess(actor, findedForum)
+	if err != nil {
+		return models.EventStream{}, code, err
 	}
-	sort := "flat"
-	if len(params["sort"]) > 0 {
-		sort = params["sort"][0]
+
+	return fu.streamEvents("thread:"+strconv.FormatInt(findedThread.Id, 10), lastEventId)
+}
+
+// SubscribeChannel resolves a channel named by a client, thread:{slug_or_id},
+// forum:{slug} or user:{nickname}, and subscribes to it. Channels of users are
+// open only to the users themselves.
+func (fu *ForumUsecase) SubscribeChannel(channel string, actor string, lastEventId int64) (models.EventStream, int, error) {
+	kind, name := channel, ""
+	if i := strings.Index(channel, ":"); i >= 0 {
+		kind, name = channel[:i], channel[i+1:]
 	}
-	desc := ""
-	comparisonSign := ">"
-	if len(params["desc"]) > 0 && params["desc"][0] == "true" {
-		desc = "desc"
-		comparisonSign = "<"
+
+	switch kind {
+	case "thread":
+		return fu.StreamThreadEvents(name, actor, lastEventId)
+	case "forum":
+		findedForum, err := fu.ForumRepo.FindForumBySlug(name)
+		if err != nil {
+			return models.EventStream{}, http.StatusNotFound, err
+		}
+		code, err := fu.checkReadAccess(actor, findedForum)
+		if err != nil {
+			return models.EventStream{}, code, err
+		}
+		return fu.streamEvents("forum:"+findedForum.Slug, lastEventId)
+	case "user":
+		findedUser, code, err := fu.checkOwner(actor, name)
+		if err != nil {
+			return models.EventStream{}, code, err
+		}
+		return fu.streamEvents("user:"+strconv.FormatInt(findedUser.Id, 10), lastEventId)
+	default:
+		return models.EventStream{}, http.StatusBadRequest, errors.New("unknown channel " + channel)
+	}
+}
+
+// streamEvents subscribes before reading the missed events, so that nothing
+// is lost in between; the caller skips live events already seen in Backlog.
+// The backlog is read in batches until it catches up with the stored events.
+func (fu *ForumUsecase) streamEvents(channel string, lastEventId int64) (models.EventStream, int, error) {
+	events, closeStream := fu.Events.Subscribe(channel)
+
+	backlog := make([]models.StreamEvent, 0)
+	for lastEventId > 0 {
+		missedEvents, err := fu.ForumRepo.GetStreamEvents(channel, lastEventId, eventsBatchSize)
+		if err != nil {
+			closeStream()
+			return models.EventStream{}, http.StatusInternalServerError, err
+		}
+		backlog = append(backlog, missedEvents...)
+		if len(missedEvents) < eventsBatchSize {
+			break
+		}
+		lastEventId = missedEvents[len(missedEvents)-1].Id
+	}
+
+	return models.EventStream{
+		Channel: channel,
+		Backlog: backlog,
+		Events:  events,
+		Close:   closeStream,
+	}, http.StatusOK, nil
+}
+
+var webhookEvents = []string{models.WebhookThreadCreated, models.WebhookPostCreated}
+
+func (fu *ForumUsecase) CreateWebhook(slug string, actor string, webhookData models.Webhook) (models.Webhook, int, error) {
+	findedForum, err := fu.ForumRepo.FindForumBySlug(slug)
+	if err != nil {
+		return models.Webhook{}, http.StatusNotFound, err
+	}
+	code, err := fu.checkModerator(actor, findedForum)
+	if err != nil {
+		return models.Webhook{}, code, err
+	}
+
+	webhookUrl, err := url.Parse(webhookData.Url)
+	if err != nil || (webhookUrl.Scheme != "http" && webhookUrl.Scheme != "https") || webhookUrl.Host == "" {
+		return models.Webhook{}, http.StatusBadRequest, errors.New("url must be an absolute http or https url")
+	}
+	if len(webhookData.Events) == 0 {
+		webhookData.Events = webhookEvents
+	}
+	for _, event := range webhookData.Events {
+		if !arrutils.StringSliceHas(webhookEvents, event) {
+			return models.Webhook{}, http.StatusBadRequest, errors.New("undefined webhook event " + event)
+		}
+	}
+	// the secret is only shown once, in the answer to this request
+	if webhookData.Secret == "" {
+		secret := make([]byte, 32)
+		_, err = rand.Read(secret)
+		if err != nil {
+			return models.Webhook{}, http.StatusInternalServerError, err
+		}
+		webhookData.Secret = hex.EncodeToString(secret)
 	}
 
-	findedPosts, err := fu.ForumRepo.GetPosts(findedThread.Id, limit, since, sort, desc, comparisonSign)
+	createdWebhook, err := fu.ForumRepo.CreateWebhook(findedForum.Id, webhookData)
 	if err != nil {
-		return []models.Post{}, http.StatusNotFound, err
+		return models.Webhook{}, http.StatusInternalServerError, err
 	}
 
-	return findedPosts, http.StatusOK, nil
+	return createdWebhook, http.StatusCreated, nil
 }
 
-func (fu *ForumUsecase) UpdateThread(threadSlugOrId string, newThread models.Thread) (models.Thread, int, error) {
-	threadId, _ := strconv.Atoi(threadSlugOrId)
+func (fu *ForumUsecase) GetWebhooks(slug string, actor string) (models.Webhooks, int, error) {
+	findedForum, err := fu.ForumRepo.FindForumBySlug(slug)
+	if err != nil {
+		return []models.Webhook{}, http.StatusNotFound, err
+	}
+	code, err := fu.checkModerator(actor, findedForum)
+	if err != nil {
+		return []models.Webhook{}, code, err
+	}
 
-	findedThread, err := fu.ForumRepo.FindThreadBySlugOrId(int64(threadId), threadSlugOrId)
+	findedWebhooks, err := fu.ForumRepo.FindWebhooks(findedForum.Id)
 	if err != nil {
-		return models.Thread{}, http.StatusNotFound, err
+		return []models.Webhook{}, http.StatusInternalServerError, err
+	}
+	for i := range findedWebhooks {
+		findedWebhooks[i].Secret = ""
 	}
 
-	if len(newThread.Title) == 0 && len(newThread.Message) == 0 {
-		return findedThread, http.StatusOK, nil
+	return findedWebhooks, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) findForumWebhook(slug string, id string, actor string) (models.Webhook, int, error) {
+	findedForum, err := fu.ForumRepo.FindForumBySlug(slug)
+	if err != nil {
+		return models.Webhook{}, http.StatusNotFound, err
+	}
+	code, err := fu.checkModerator(actor, findedForum)
+	if err != nil {
+		return models.Webhook{}, code, err
 	}
 
-	if len(newThread.Title) == 0 {
-		newThread.Title = findedThread.Title
+	webhookId, _ := strconv.ParseInt(id, 10, 64)
+	findedWebhook, err := fu.ForumRepo.FindWebhook(webhookId, findedForum.Id)
+	if err != nil {
+		return models.Webhook{}, http.StatusNotFound, err
 	}
-	if len(newThread.Message) == 0 {
-		newThread.Message = findedThread.Message
+
+	return findedWebhook, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) DeleteWebhook(slug string, id string, actor string) (int, error) {
+	findedWebhook, code, err := fu.findForumWebhook(slug, id, actor)
+	if err != nil {
+		return code, err
 	}
 
-	updatedThread, err := fu.ForumRepo.UpdateThread(findedThread.Id, newThread)
+	err = fu.ForumRepo.DeleteWebhook(findedWebhook.Id)
 	if err != nil {
-		return models.Thread{}, http.StatusNotFound, err
+		return http.StatusInternalServerError, err
 	}
 
-	return updatedThread, http.StatusOK, nil
+	return http.StatusOK, nil
 }
 
-func (fu *ForumUsecase) GetForumUsers(forumSlug string, params map[string][]string) (models.Users, int, error) {
-	findedForum, err := fu.ForumRepo.FindForumBySlug(forumSlug)
+func (fu *ForumUsecase) GetWebhookDeliveries(slug string, id string, actor string, params map[string][]string) (models.WebhookDeliveries, int, error) {
+	findedWebhook, code, err := fu.findForumWebhook(slug, id, actor)
 	if err != nil {
-		return []models.User{}, http.StatusNotFound, err
+		return []models.WebhookDelivery{}, code, err
 	}
 
 	limit := "100"
 	if len(params["limit"]) > 0 {
 		limit = params["limit"][0]
 	}
+	if _, err := strconv.Atoi(limit); err != nil {
+		return []models.WebhookDelivery{}, http.StatusBadRequest, errors.New("limit must be a number")
+	}
 	since := ""
 	if len(params["since"]) > 0 {
 		since = params["since"][0]
+		if _, err := strconv.ParseInt(since, 10, 64); err != nil {
+			return []models.WebhookDelivery{}, http.StatusBadRequest, errors.New("since must be a delivery id")
+		}
 	}
-	desc := ""
-	comparisonSign := ">"
-	if len(params["desc"]) > 0 && params["desc"][0] == "true" {
-		desc = "desc"
-		comparisonSign = "<"
+
+	findedDeliveries, err := fu.ForumRepo.GetWebhookDeliveries(findedWebhook.Id, limit, since)
+	if err != nil {
+		return []models.WebhookDelivery{}, http.StatusInternalServerError, err
 	}
 
-	findedUsers, err := fu.ForumRepo.GetForumUsers(findedForum.Id, limit, since, desc, comparisonSign)
+	return findedDeliveries, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) RedeliverWebhook(slug string, id string, deliveryId string, actor string) (models.WebhookDelivery, int, error) {
+	findedWebhook, code, err := fu.findForumWebhook(slug, id, actor)
 	if err != nil {
-		return []models.User{}, http.StatusNotFound, err
+		return models.WebhookDelivery{}, code, err
 	}
 
-	return findedUsers, http.StatusOK, nil
+	originalId, _ := strconv.ParseInt(deliveryId, 10, 64)
+	createdDelivery, err := fu.ForumRepo.RedeliverWebhookDelivery(findedWebhook.Id, originalId)
+	if err != nil {
+		return models.WebhookDelivery{}, http.StatusNotFound, err
+	}
+
+	return createdDelivery, http.StatusCreated, nil
+}
+
+var leaderboardSorts = []string{"posts", "threads"}
+
+const (
+	maxStatsDays  = 365
+	maxStatsLimit = 100
+)
+
+// statsParam reads a positive number parameter of the stats, capped so that
+// the rollups are read in bounded ranges.
+func statsParam(params map[string][]string, name string, value int, max int) (int, error) {
+	if len(params[name]) > 0 {
+		parsed, err := strconv.Atoi(params[name][0])
+		if err != nil || parsed <= 0 {
+			return 0, errors.New(name + " must be a positive number")
+		}
+		value = parsed
+	}
+	if value > max {
+		value = max
+	}
+	return value, nil
+}
+
+func (fu *ForumUsecase) GetForumStats(slug string, actor string, params map[string][]string) (models.ForumStats, int, error) {
+	findedForum, err := fu.ForumRepo.FindForumBySlug(slug)
+	if err != nil {
+		return models.ForumStats{}, http.StatusNotFound, err
+	}
+	code, err := fu.checkReadAccess(actor, findedForum)
+	if err != nil {
+		return models.ForumStats{}, code, err
+	}
+
+	days, err := statsParam(params, "days", 30, maxStatsDays)
+	if err != nil {
+		return models.ForumStats{}, http.StatusBadRequest, err
+	}
+	limit, err := statsParam(params, "limit", 10, maxStatsLimit)
+	if err != nil {
+		return models.ForumStats{}, http.StatusBadRequest, err
+	}
+
+	forumStats := models.ForumStats{
+		Forum:   findedForum.Slug,
+		Posts:   findedForum.Posts,
+		Threads: findedForum.Threads,
+	}
+	forumStats.Daily, err = fu.ForumRepo.GetForumDailyStats(findedForum.Id, days)
+	if err != nil {
+		return models.ForumStats{}, http.StatusInternalServerError, err
+	}
+	forumStats.TopPosters, err = fu.ForumRepo.GetTopPosters(findedForum.Id, limit)
+	if err != nil {
+		return models.ForumStats{}, http.StatusInternalServerError, err
+	}
+	forumStats.TopThreads, err = fu.ForumRepo.GetTopThreads(findedForum.Id, days, limit)
+	if err != nil {
+		return models.ForumStats{}, http.StatusInternalServerError, err
+	}
+	forumStats.ActiveUsers, err = fu.ForumRepo.CountActiveUsers(findedForum.Id)
+	if err != nil {
+		return models.ForumStats{}, http.StatusInternalServerError, err
+	}
+
+	return forumStats, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) GetLeaderboard(params map[string][]string) (models.Leaderboard, int, error) {
+	sort := "posts"
+	if len(params["sort"]) > 0 {
+		sort = params["sort"][0]
+	}
+	if !arrutils.StringSliceHas(leaderboardSorts, sort) {
+		return []models.UserStats{}, http.StatusBadRequest, errors.New("undefined sort type")
+	}
+	limit, err := statsParam(params, "limit", 10, maxStatsLimit)
+	if err != nil {
+		return []models.UserStats{}, http.StatusBadRequest, err
+	}
+
+	leaderboard, err := fu.ForumRepo.GetLeaderboard(sort, limit)
+	if err != nil {
+		return []models.UserStats{}, http.StatusInternalServerError, err
+	}
+
+	return leaderboard, http.StatusOK, nil
+}
+
+func (fu *ForumUsecase) GetTrending(actor string, params map[string][]string) (models.TrendingThreads, int, error) {
+	code, err := fu.checkActor(actor)
+	if err != nil {
+		return []models.TrendingThread{}, code, err
+	}
+
+	windows := fu.Trending.Windows()
+	if len(windows) == 0 {
+		return []models.TrendingThread{}, http.StatusNotFound, errors.New("no trending windows are configured")
+	}
+
+	// the day is the default window when it is configured
+	filter := models.TrendingFilter{Window: windows[0], AnonymousOnly: actor == ""}
+	if arrutils.StringSliceHas(windows, "24h") {
+		filter.Window = "24h"
+	}
+	if len(params["window"]) > 0 {
+		filter.Window = params["window"][0]
+		if !arrutils.StringSliceHas(windows, filter.Window) {
+			return []models.TrendingThread{}, http.StatusBadRequest, errors.New("window must be one of " + strings.Join(windows, ", "))
+		}
+	}
+	if len(params["forum"]) > 0 {
+		findedForum, err := fu.ForumRepo.FindForumBySlug(params["forum"][0])
+		if err != nil {
+			return []models.TrendingThread{}, http.StatusNotFound, err
+		}
+		code, err := fu.checkReadAccess(actor, findedForum)
+		if err != nil {
+			return []models.TrendingThread{}, code, err
+		}
+		filter.Forum = findedForum.Slug
+	}
+	filter.Tags, filter.MatchAllTags, err = tagParams(params)
+	if err != nil {
+		return []models.TrendingThread{}, http.StatusBadRequest, err
+	}
+	limit, err := statsParam(params, "limit", 20, maxStatsLimit)
+	if err != nil {
+		return []models.TrendingThread{}, http.StatusBadRequest, err
+	}
+	filter.Limit = limit
+
+	trendingThreads, err := fu.ForumRepo.GetTrending(filter)
+	if err != nil {
+		return []models.TrendingThread{}, http.StatusInternalServerError, err
+	}
+
+	return trendingThreads, http.StatusOK, nil
 }
 
-func (fu *ForumUsecase) GetPostInfo(id string, params map[string][]string) (models.PostFull, int, error) {
+func (fu *ForumUsecase) GetPostInfo(id string, actor string, params map[string][]string) (models.PostFull, int, error) {
 	postId, _ := strconv.Atoi(id)
 	withUser, withForum, withThread := false, false, false
 	related := params["related"]
@@ -345,6 +2196,15 @@ func (fu *ForumUsecase) GetPostInfo(id string, params map[string][]string) (mode
 		return models.PostFull{}, http.StatusNotFound, err
 	}
 
+	findedForum, err := fu.ForumRepo.FindForumBySlug(findedPostInfo.Post.Forum)
+	if err != nil {
+		return models.PostFull{}, http.StatusNotFound, err
+	}
+	code, err := fu.checkReadAccess(actor, findedForum)
+	if err != nil {
+		return models.PostFull{}, code, err
+	}
+
 	return findedPostInfo, http.StatusOK, nil
 }
 
@@ -363,7 +2223,7 @@ func (fu *ForumUsecase) UpdatePost(id string, newPost models.Post) (models.Post,
 		findedPost.Message = newPost.Message
 	}
 
-	updatedPost, err := fu.ForumRepo.UpdatePost(findedPost)
+	updatedPost, err := fu.ForumRepo.UpdatePost(findedPost, parseMentions(findedPost.Message, findedPost.Author))
 	if err != nil {
 		return models.Post{}, http.StatusNotFound, err
 	}
@@ -371,6 +2231,38 @@ func (fu *ForumUsecase) UpdatePost(id string, newPost models.Post) (models.Post,
 	return updatedPost, http.StatusOK, nil
 }
 
+// DeletePost removes the post with its replies, which only its author or a
+// moderator of the forum may do.
+func (fu *ForumUsecase) DeletePost(id string, actor string) (models.Thread, int, error) {
+	postId, _ := strconv.Atoi(id)
+
+	findedPost, err := fu.ForumRepo.FindPost(int64(postId))
+	if err != nil {
+		return models.Thread{}, http.StatusNotFound, err
+	}
+
+	if actor == "" {
+		return models.Thread{}, http.StatusUnauthorized, errors.New("authentication required")
+	}
+	if !strings.EqualFold(actor, findedPost.Author) {
+		findedForum, err := fu.ForumRepo.FindForumBySlug(findedPost.Forum)
+		if err != nil {
+			return models.Thread{}, http.StatusNotFound, err
+		}
+		code, err := fu.checkModerator(actor, findedForum)
+		if err != nil {
+			return models.Thread{}, code, err
+		}
+	}
+
+	updatedThread, err := fu.ForumRepo.DeletePost(findedPost)
+	if err != nil {
+		return models.Thread{}, http.StatusInternalServerError, err
+	}
+
+	return updatedThread, http.StatusOK, nil
+}
+
 func (fu *ForumUsecase) ServiceStatus() (models.Status, int, error) {
 	curServiceStatis, err := fu.ForumRepo.ServiceStatus()
 	if err != nil {
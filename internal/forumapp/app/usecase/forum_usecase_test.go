@@ -0,0 +1,379 @@
+package usecase
+
+import (
+	"forumApp/internal/forumapp/models"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// readThread replays reading the pages of a thread with the posts 1..postsCount
+// the way GetPosts does and returns the marker and the posts read past it after
+// each page.
+func readThread(t *testing.T, postsCount int64, pages [][]int64) ([]int64, [][]int64) {
+	t.Helper()
+
+	var marker int64
+	readPastMarker := []int64{}
+	markers := make([]int64, 0, len(pages))
+	readPastMarkers := make([][]int64, 0, len(pages))
+	for _, page := range pages {
+		readPostIds := append(append([]int64{}, readPastMarker...), page...)
+
+		var firstUnreadId int64
+		for postId := marker + 1; postId <= postsCount; postId++ {
+			read := false
+			for _, readPostId := range readPostIds {
+				read = read || readPostId == postId
+			}
+			if !read {
+				firstUnreadId = postId
+				break
+			}
+		}
+
+		marker, readPastMarker = readMarker(marker, readPostIds, firstUnreadId)
+		markers = append(markers, marker)
+		readPastMarkers = append(readPastMarkers, readPastMarker)
+	}
+	return markers, readPastMarkers
+}
+
+func TestReadMarker(t *testing.T) {
+	// posts 3, 5 and 6 answer the post 1, the post 4 answers the post 2, so
+	// the tree order is 1, 3, 5, 6, 2, 4
+	tests := []struct {
+		name                string
+		pages               [][]int64
+		wantMarkers         []int64
+		wantReadPastMarkers [][]int64
+	}{
+		{
+			name:                "flat",
+			pages:               [][]int64{{1, 2}, {3, 4}, {5, 6}},
+			wantMarkers:         []int64{2, 4, 6},
+			wantReadPastMarkers: [][]int64{{}, {}, {}},
+		},
+		{
+			name:                "flat desc",
+			pages:               [][]int64{{6, 5}, {4, 3}, {2, 1}},
+			wantMarkers:         []int64{0, 0, 6},
+			wantReadPastMarkers: [][]int64{{6, 5}, {6, 5, 4, 3}, {}},
+		},
+		{
+			name:                "tree",
+			pages:               [][]int64{{1, 3}, {5, 6}, {2, 4}},
+			wantMarkers:         []int64{1, 1, 6},
+			wantReadPastMarkers: [][]int64{{3}, {3, 5, 6}, {}},
+		},
+		{
+			name:                "parent_tree",
+			pages:               [][]int64{{1, 3, 5, 6}, {2, 4}},
+			wantMarkers:         []int64{1, 6},
+			wantReadPastMarkers: [][]int64{{3, 5, 6}, {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markers, readPastMarkers := readThread(t, 6, tt.pages)
+			if !reflect.DeepEqual(markers, tt.wantMarkers) {
+				t.Errorf("markers = %v, want %v", markers, tt.wantMarkers)
+			}
+			if !reflect.DeepEqual(readPastMarkers, tt.wantReadPastMarkers) {
+				t.Errorf("posts read past the marker = %v, want %v", readPastMarkers, tt.wantReadPastMarkers)
+			}
+		})
+	}
+}
+
+func TestReadMarkerKeepsSkippedPostsUnread(t *testing.T) {
+	// the second page of the tree sort was read first, the posts 1, 2 and 3
+	// are still unread
+	marker, readPastMarker := readMarker(0, []int64{5, 6}, 1)
+	if marker != 0 {
+		t.Errorf("marker = %d, want 0", marker)
+	}
+	if !reflect.DeepEqual(readPastMarker, []int64{5, 6}) {
+		t.Errorf("posts read past the marker = %v, want [5 6]", readPastMarker)
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "no tags", tags: nil, want: nil},
+		{name: "empty", tags: []string{}, want: []string{}},
+		{name: "lower case and trimmed", tags: []string{" Go ", "SQL"}, want: []string{"go", "sql"}},
+		{name: "duplicates", tags: []string{"go", "Go", " GO"}, want: []string{"go"}},
+		{name: "blank tags", tags: []string{"", "  ", "go"}, want: []string{"go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeTags(tt.tags)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeTags(%q) = %q, want %q", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		author  string
+		want    []string
+	}{
+		{name: "no mentions", message: "hello", author: "bob", want: nil},
+		{name: "mentions", message: "@alice and @j.doe, look", author: "bob", want: []string{"alice", "j.doe"}},
+		{name: "trailing dot", message: "thanks @alice.", author: "bob", want: []string{"alice"}},
+		{name: "duplicates", message: "@Alice @alice @ALICE", author: "bob", want: []string{"Alice"}},
+		{name: "author", message: "@Bob @alice", author: "bob", want: []string{"alice"}},
+		{name: "email", message: "write to alice@mail.ru", author: "bob", want: nil},
+		{name: "double at", message: "@@alice", author: "bob", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseMentions(tt.message, tt.author)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMentions(%q, %q) = %q, want %q", tt.message, tt.author, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVoteListParams(t *testing.T) {
+	tests := []struct {
+		name               string
+		params             map[string][]string
+		wantLimit          string
+		wantSince          string
+		wantDesc           string
+		wantComparisonSign string
+		wantErr            bool
+	}{
+		{name: "defaults", params: map[string][]string{}, wantLimit: "100", wantDesc: "desc", wantComparisonSign: "<"},
+		{
+			name:      "newest first",
+			params:    map[string][]string{"limit": {"10"}, "since": {"42"}, "desc": {"true"}},
+			wantLimit: "10", wantSince: "42", wantDesc: "desc", wantComparisonSign: "<",
+		},
+		{
+			name:      "oldest first",
+			params:    map[string][]string{"since": {"42"}, "desc": {"false"}},
+			wantLimit: "100", wantSince: "42", wantDesc: "", wantComparisonSign: ">",
+		},
+		{name: "bad limit", params: map[string][]string{"limit": {"ten"}}, wantErr: true},
+		{name: "bad since", params: map[string][]string{"since": {"2020-01-01"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, since, desc, comparisonSign, err := voteListParams(tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if limit != tt.wantLimit || since != tt.wantSince || desc != tt.wantDesc || comparisonSign != tt.wantComparisonSign {
+				t.Errorf("voteListParams() = %q, %q, %q, %q, want %q, %q, %q, %q",
+					limit, since, desc, comparisonSign, tt.wantLimit, tt.wantSince, tt.wantDesc, tt.wantComparisonSign)
+			}
+		})
+	}
+}
+
+// threadsRepo is a forum anyone can read with the pinned threads and the
+// regular ones in the order of the listing.
+type threadsRepo struct {
+	models.ForumRepository
+	pinned  []models.Thread
+	threads []models.Thread
+}
+
+func (tr *threadsRepo) FindForumBySlug(slug string) (models.Forum, error) {
+	return models.Forum{Id: 1, Slug: slug, User: "owner"}, nil
+}
+
+func (tr *threadsRepo) GetForumSettings(forumId int64) (models.ForumSettings, error) {
+	return models.ForumSettings{AnonymousRead: true}, nil
+}
+
+func (tr *threadsRepo) FindUserByNickname(nickname string) (models.User, error) {
+	return models.User{Id: 1, Nickname: nickname}, nil
+}
+
+func (tr *threadsRepo) FindPinnedThreads(slug string, withSubforums bool) ([]models.Thread, error) {
+	return tr.pinned, nil
+}
+
+func (tr *threadsRepo) FindThreadsBySlugWithParams(slug string, limit string, since string, sort string, desc string, comparisonSign string, withSubforums bool, tags []string, matchAllTags bool) ([]models.Thread, error) {
+	pageSize, _ := strconv.Atoi(limit)
+	findedThreads := make([]models.Thread, 0)
+	listed := since == ""
+	for _, thread := range tr.threads {
+		if listed && len(findedThreads) < pageSize {
+			findedThreads = append(findedThreads, thread)
+		}
+		listed = listed || strconv.FormatInt(thread.Id, 10) == since
+	}
+	return findedThreads, nil
+}
+
+func (tr *threadsRepo) FindThreadBySlugOrId(threadId int64, slug string) (models.Thread, error) {
+	for _, thread := range append(tr.pinned, tr.threads...) {
+		if thread.Id == threadId {
+			return thread, nil
+		}
+	}
+	return models.Thread{}, nil
+}
+
+func (tr *threadsRepo) CountPinnedThreads(slug string) (int, int, error) {
+	var pinned, announcements int
+	for _, thread := range tr.pinned {
+		if thread.Pinned {
+			pinned++
+		}
+		if thread.Announcement {
+			announcements++
+		}
+	}
+	return pinned, announcements, nil
+}
+
+func (tr *threadsRepo) PinThread(threadId int64, pinData models.ThreadPin) (models.Thread, error) {
+	return models.Thread{Id: threadId, Pinned: pinData.Pinned, Announcement: pinData.Announcement}, nil
+}
+
+func TestGetThreadsPaginatesPastPinnedThreads(t *testing.T) {
+	repo := &threadsRepo{}
+	for i := int64(1); i <= 3; i++ {
+		repo.pinned = append(repo.pinned, models.Thread{Id: 100 + i, Pinned: true})
+	}
+	for i := int64(1); i <= 5; i++ {
+		repo.threads = append(repo.threads, models.Thread{Id: i})
+	}
+	fu := &ForumUsecase{ForumRepo: repo}
+
+	var pages [][]int64
+	since := ""
+	for len(pages) < 5 {
+		params := map[string][]string{"limit": {"2"}, "sort": {"votes"}}
+		if since != "" {
+			params["since"] = []string{since}
+		}
+		threads, code, err := fu.GetThreads("forum", "", params)
+		if err != nil || code != http.StatusOK {
+			t.Fatalf("GetThreads() = %d, %v", code, err)
+		}
+		if len(threads) == 0 {
+			break
+		}
+
+		page := make([]int64, 0, len(threads))
+		for _, thread := range threads {
+			page = append(page, thread.Id)
+		}
+		pages = append(pages, page)
+		since = strconv.FormatInt(threads[len(threads)-1].Id, 10)
+	}
+
+	// the pinned threads come on top of the limit of the first page only
+	want := [][]int64{{101, 102, 103, 1, 2}, {3, 4}, {5}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+}
+
+func TestPinThreadCapsPinnedThreads(t *testing.T) {
+	repo := &threadsRepo{threads: []models.Thread{{Id: 1, Forum: "forum"}}}
+	for i := int64(0); i < maxPinnedThreads; i++ {
+		repo.pinned = append(repo.pinned, models.Thread{Id: 100 + i, Forum: "forum", Pinned: true})
+	}
+	fu := &ForumUsecase{ForumRepo: repo}
+
+	_, code, err := fu.PinThread("1", "owner", models.ThreadPin{Pinned: true})
+	if err == nil || code != http.StatusConflict {
+		t.Errorf("pinning one more thread = %d, %v, want %d", code, err, http.StatusConflict)
+	}
+
+	// reordering an already pinned thread is not capped
+	_, code, err = fu.PinThread("100", "owner", models.ThreadPin{Pinned: true, Order: 1})
+	if err != nil || code != http.StatusOK {
+		t.Errorf("repinning a thread = %d, %v, want %d", code, err, http.StatusOK)
+	}
+}
+
+func TestTagParams(t *testing.T) {
+	tags, matchAllTags, err := tagParams(map[string][]string{"tag": {"Go,sql", "go"}, "tag_mode": {"or"}})
+	if err != nil {
+		t.Fatalf("tagParams() error = %v", err)
+	}
+	if !reflect.DeepEqual(tags, []string{"go", "sql"}) || matchAllTags {
+		t.Errorf("tagParams() = %q, %v, want [go sql], false", tags, matchAllTags)
+	}
+
+	if _, _, err := tagParams(map[string][]string{"tag_mode": {"xor"}}); err == nil {
+		t.Error("tagParams() accepts tag_mode xor")
+	}
+}
+
+// privateForumRepo is a forum only its members can read.
+type privateForumRepo struct {
+	models.ForumRepository
+	searchedForumId int64
+}
+
+func (pr *privateForumRepo) FindForumBySlug(slug string) (models.Forum, error) {
+	return models.Forum{Id: 7, Slug: slug, User: "owner"}, nil
+}
+
+func (pr *privateForumRepo) GetForumSettings(forumId int64) (models.ForumSettings, error) {
+	return models.ForumSettings{AnonymousRead: false}, nil
+}
+
+func (pr *privateForumRepo) FindUserByNickname(nickname string) (models.User, error) {
+	return models.User{Id: 1, Nickname: nickname}, nil
+}
+
+func (pr *privateForumRepo) SearchUsers(query string, forumId int64, limit int, offset int) ([]models.User, error) {
+	pr.searchedForumId = forumId
+	return []models.User{{Id: 1, Nickname: "owner"}}, nil
+}
+
+func TestSearchUsersChecksForumReadAccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		actor    string
+		params   map[string][]string
+		wantCode int
+	}{
+		{"anonymous in a private forum", "", map[string][]string{"query": {"own"}, "forum": {"private"}}, http.StatusUnauthorized},
+		{"member in a private forum", "reader", map[string][]string{"query": {"own"}, "forum": {"private"}}, http.StatusOK},
+		{"anonymous without a forum", "", map[string][]string{"query": {"own"}}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &privateForumRepo{}
+			fu := &ForumUsecase{ForumRepo: repo}
+
+			_, code, _ := fu.SearchUsers(tt.actor, tt.params)
+			if code != tt.wantCode {
+				t.Errorf("SearchUsers() code = %d, want %d", code, tt.wantCode)
+			}
+			if code != http.StatusOK && repo.searchedForumId != 0 {
+				t.Errorf("the users of forum %d were searched", repo.searchedForumId)
+			}
+		})
+	}
+}
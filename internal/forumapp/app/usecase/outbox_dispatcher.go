@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"forumApp/internal/forumapp/models"
+	"forumApp/internal/pkg/arrutils"
+	"log"
+	"time"
+)
+
+const (
+	outboxBatchSize      = 50
+	outboxPublishTimeout = 10 * time.Second
+	outboxBaseBackoff    = time.Second
+	outboxMaxBackoff     = 10 * time.Minute
+	outboxRetention      = 7 * 24 * time.Hour
+)
+
+// OutboxSink is a destination of the domain events. Publish may be called
+// more than once for the same event, consumers tell duplicates apart by the
+// event id.
+type OutboxSink interface {
+	Name() string
+	Publish(ctx context.Context, event models.OutboxEvent) error
+}
+
+// OutboxDispatcher publishes the events of the outbox to the sinks. An event
+// is retried until every sink has accepted it, so delivery is at least once.
+type OutboxDispatcher struct {
+	repo         models.ForumRepository
+	sinks        []OutboxSink
+	pollInterval time.Duration
+}
+
+func NewOutboxDispatcher(fr models.ForumRepository, pollInterval time.Duration, sinks ...OutboxSink) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		repo:         fr,
+		sinks:        sinks,
+		pollInterval: pollInterval,
+	}
+}
+
+func (od *OutboxDispatcher) Run(ctx context.Context) {
+	go od.prune(ctx)
+
+	ticker := time.NewTicker(od.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			od.publishPending(ctx)
+		}
+	}
+}
+
+func (od *OutboxDispatcher) publishPending(ctx context.Context) {
+	// the lease outlives the publishing of a whole batch to every sink
+	lease := time.Duration(len(od.sinks)+1) * outboxPublishTimeout * outboxBatchSize
+
+	for ctx.Err() == nil {
+		events, err := od.repo.ClaimOutboxEvents(outboxBatchSize, lease)
+		if err != nil {
+			log.Printf("outbox dispatcher: %s", err)
+			return
+		}
+
+		for _, event := range events {
+			od.publish(ctx, event)
+		}
+
+		if len(events) < outboxBatchSize {
+			return
+		}
+	}
+}
+
+func (od *OutboxDispatcher) publish(ctx context.Context, event models.OutboxEvent) {
+	publishedTo := event.PublishedTo
+	var publishErr error
+	for _, sink := range od.sinks {
+		if arrutils.StringSliceHas(publishedTo, sink.Name()) {
+			continue
+		}
+
+		sinkCtx, cancel := context.WithTimeout(ctx, outboxPublishTimeout)
+		err := sink.Publish(sinkCtx, event)
+		cancel()
+		if err != nil {
+			publishErr = err
+			log.Printf("outbox dispatcher: event %d to %s: %s", event.Id, sink.Name(), err)
+			continue
+		}
+		publishedTo = append(publishedTo, sink.Name())
+	}
+	if publishedTo == nil {
+		publishedTo = []string{}
+	}
+
+	var err error
+	if publishErr != nil {
+		retryAt := time.Now().Add(retryBackoff(event.Attempts+1, outboxBaseBackoff, outboxMaxBackoff))
+		err = od.repo.RetryOutboxEvent(event.Id, publishedTo, retryAt, publishErr.Error())
+	} else {
+		err = od.repo.MarkOutboxEventPublished(event.Id, publishedTo)
+	}
+	if err != nil {
+		log.Printf("outbox dispatcher: %s", err)
+	}
+}
+
+func (od *OutboxDispatcher) prune(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := od.repo.DeleteOutboxEvents(time.Now().Add(-outboxRetention))
+			if err != nil {
+				log.Printf("outbox dispatcher: %s", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"forumApp/internal/forumapp/models"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// LogSink writes the events to the log.
+type LogSink struct{}
+
+func (LogSink) Name() string {
+	return "log"
+}
+
+func (LogSink) Publish(ctx context.Context, event models.OutboxEvent) error {
+	log.Printf("outbox event %d: %s %s/%s %s", event.Id, event.Type, event.Aggregate, event.AggregateId, event.Payload)
+	return nil
+}
+
+// WebhookSink posts the events to a single endpoint, signed like the forum
+// webhooks.
+type WebhookSink struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhookSink(url string, secret string) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		secret: secret,
+		client: &http.Client{},
+	}
+}
+
+func (ws *WebhookSink) Name() string {
+	return "webhook"
+}
+
+func (ws *WebhookSink) Publish(ctx context.Context, event models.OutboxEvent) error {
+	body, err := event.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ws.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event.Type)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(event.Id, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(ws.secret, body))
+
+	resp, err := ws.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, webhookErrorMaxSize))
+		return &webhookError{status: resp.Status, body: string(body)}
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
+
+// EventBus hands the events to the handlers subscribed in this process. A
+// handler that fails makes the event be published again to all of them.
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]func(models.OutboxEvent) error
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{
+		handlers: make(map[string][]func(models.OutboxEvent) error),
+	}
+}
+
+// Subscribe registers the handler for the events of the type, or for every
+// event if the type is empty.
+func (eb *EventBus) Subscribe(eventType string, handler func(models.OutboxEvent) error) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.handlers[eventType] = append(eb.handlers[eventType], handler)
+}
+
+func (eb *EventBus) Name() string {
+	return "bus"
+}
+
+func (eb *EventBus) Publish(ctx context.Context, event models.OutboxEvent) error {
+	eb.mu.RLock()
+	handlers := make([]func(models.OutboxEvent) error, 0, len(eb.handlers[event.Type])+len(eb.handlers[""]))
+	handlers = append(handlers, eb.handlers[event.Type]...)
+	handlers = append(handlers, eb.handlers[""]...)
+	eb.mu.RUnlock()
+
+	for _, handler := range handlers {
+		err := handler(event)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
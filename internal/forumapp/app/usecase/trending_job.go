@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"forumApp/internal/forumapp/models"
+	"log"
+	"sort"
+	"time"
+)
+
+// TrendingJob periodically ranks the threads by their posts and votes in each
+// window into the trending table, which is all the trending endpoint reads.
+type TrendingJob struct {
+	repo     models.ForumRepository
+	interval time.Duration
+	windows  map[string]time.Duration
+}
+
+func NewTrendingJob(fr models.ForumRepository, interval time.Duration, windows map[string]time.Duration) *TrendingJob {
+	return &TrendingJob{
+		repo:     fr,
+		interval: interval,
+		windows:  windows,
+	}
+}
+
+// Windows returns the names of the ranked windows, shortest first.
+func (tj *TrendingJob) Windows() []string {
+	names := make([]string, 0, len(tj.windows))
+	for name := range tj.windows {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return tj.windows[names[i]] < tj.windows[names[j]]
+	})
+	return names
+}
+
+func (tj *TrendingJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(tj.interval)
+	defer ticker.Stop()
+
+	for {
+		tj.refresh()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (tj *TrendingJob) refresh() {
+	for name, window := range tj.windows {
+		err := tj.repo.RefreshTrending(name, window)
+		if err != nil {
+			log.Printf("trending job: %s: %s", name, err)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"forumApp/internal/forumapp/models"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	webhookBatchSize    = 20
+	webhookBaseBackoff  = 10 * time.Second
+	webhookMaxBackoff   = time.Hour
+	webhookErrorMaxSize = 1024
+	// WebhookSignatureHeader carries the hex HMAC-SHA256 of the body keyed
+	// with the webhook secret.
+	WebhookSignatureHeader = "X-Forum-Signature"
+	WebhookEventHeader     = "X-Forum-Event"
+	WebhookDeliveryHeader  = "X-Forum-Delivery"
+)
+
+// WebhookWorker sends the queued webhook deliveries. Several workers, in one
+// or many API instances, can share the queue.
+type WebhookWorker struct {
+	repo         models.ForumRepository
+	client       *http.Client
+	pollInterval time.Duration
+	timeout      time.Duration
+	maxAttempts  int32
+}
+
+func NewWebhookWorker(fr models.ForumRepository, pollInterval time.Duration, timeout time.Duration, maxAttempts int) *WebhookWorker {
+	return &WebhookWorker{
+		repo:         fr,
+		client:       &http.Client{Timeout: timeout},
+		pollInterval: pollInterval,
+		timeout:      timeout,
+		maxAttempts:  int32(maxAttempts),
+	}
+}
+
+func (ww *WebhookWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(ww.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ww.deliverPending(ctx)
+		}
+	}
+}
+
+func (ww *WebhookWorker) deliverPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		// the lease outlives the sending of a whole batch
+		jobs, err := ww.repo.ClaimWebhookDeliveries(webhookBatchSize, 2*ww.timeout*webhookBatchSize)
+		if err != nil {
+			log.Printf("webhook worker: %s", err)
+			return
+		}
+
+		for _, job := range jobs {
+			delivery := ww.deliver(ctx, job)
+			err = ww.repo.CompleteWebhookDelivery(delivery)
+			if err != nil {
+				log.Printf("webhook worker: %s", err)
+			}
+		}
+
+		if len(jobs) < webhookBatchSize {
+			return
+		}
+	}
+}
+
+func (ww *WebhookWorker) deliver(ctx context.Context, job models.WebhookJob) models.WebhookDelivery {
+	delivery := job.Delivery
+	delivery.Attempts++
+	delivery.StatusCode = 0
+	delivery.Error = ""
+
+	statusCode, err := ww.send(ctx, job)
+	delivery.StatusCode = int32(statusCode)
+	if err == nil {
+		now := time.Now()
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		return delivery
+	}
+
+	delivery.Error = err.Error()
+	if delivery.Attempts >= ww.maxAttempts {
+		delivery.Status = models.DeliveryFailed
+		return delivery
+	}
+	delivery.NextAttempt = time.Now().Add(retryBackoff(delivery.Attempts, webhookBaseBackoff, webhookMaxBackoff))
+	return delivery
+}
+
+func (ww *WebhookWorker) send(ctx context.Context, job models.WebhookJob) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.Url, bytes.NewReader(job.Delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, job.Delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(job.Delivery.Id, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(job.Secret, job.Delivery.Payload))
+
+	resp, err := ww.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, webhookErrorMaxSize))
+		return resp.StatusCode, &webhookError{status: resp.Status, body: string(body)}
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+type webhookError struct {
+	status string
+	body   string
+}
+
+func (we *webhookError) Error() string {
+	if we.body == "" {
+		return we.status
+	}
+	return we.status + ": " + we.body
+}
+
+// retryBackoff doubles the delay after every failed attempt.
+func retryBackoff(attempts int32, base time.Duration, max time.Duration) time.Duration {
+	backoff := base
+	for i := int32(1); i < attempts && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	return backoff
+}
+
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
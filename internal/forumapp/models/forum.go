@@ -1,10 +1,20 @@
 package models
 
 type Forum struct {
-	Id      int64  `json:"id,omitempty"`
-	Title   string `json:"title"`
-	User    string `json:"user"`
-	Slug    string `json:"slug"`
-	Posts   int64  `json:"posts,omitempty"`
-	Threads int32  `json:"threads,omitempty"`
+	Id           int64   `json:"id,omitempty"`
+	Title        string  `json:"title"`
+	User         string  `json:"user"`
+	Slug         string  `json:"slug"`
+	Posts        int64   `json:"posts,omitempty"`
+	Threads      int32   `json:"threads,omitempty"`
+	Description  string  `json:"description,omitempty"`
+	Parent       string  `json:"parent,omitempty"`
+	Position     int32   `json:"position,omitempty"`
+	Category     bool    `json:"category,omitempty"`
+	TotalPosts   int64   `json:"total_posts,omitempty"`
+	TotalThreads int32   `json:"total_threads,omitempty"`
+	Children     []Forum `json:"children,omitempty"`
 }
+
+//easyjson:json
+type Forums []Forum
@@ -17,7 +17,73 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjsonC8d74561DecodeForumAppInternalForumappModels(in *jlexer.Lexer, out *Forum) {
+func easyjsonC8d74561DecodeForumAppInternalForumappModels(in *jlexer.Lexer, out *Forums) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(Forums, 0, 0)
+			} else {
+				*out = Forums{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v1 Forum
+			(v1).UnmarshalEasyJSON(in)
+			*out = append(*out, v1)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeForumAppInternalForumappModels(out *jwriter.Writer, in Forums) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v2, v3 := range in {
+			if v2 > 0 {
+				out.RawByte(',')
+			}
+			(v3).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Forums) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeForumAppInternalForumappModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Forums) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeForumAppInternalForumappModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Forums) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeForumAppInternalForumappModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Forums) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeForumAppInternalForumappModels(l, v)
+}
+func easyjsonC8d74561DecodeForumAppInternalForumappModels1(in *jlexer.Lexer, out *Forum) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -48,6 +114,41 @@ func easyjsonC8d74561DecodeForumAppInternalForumappModels(in *jlexer.Lexer, out
 			out.Posts = int64(in.Int64())
 		case "threads":
 			out.Threads = int32(in.Int32())
+		case "description":
+			out.Description = string(in.String())
+		case "parent":
+			out.Parent = string(in.String())
+		case "position":
+			out.Position = int32(in.Int32())
+		case "category":
+			out.Category = bool(in.Bool())
+		case "total_posts":
+			out.TotalPosts = int64(in.Int64())
+		case "total_threads":
+			out.TotalThreads = int32(in.Int32())
+		case "children":
+			if in.IsNull() {
+				in.Skip()
+				out.Children = nil
+			} else {
+				in.Delim('[')
+				if out.Children == nil {
+					if !in.IsDelim(']') {
+						out.Children = make([]Forum, 0, 0)
+					} else {
+						out.Children = []Forum{}
+					}
+				} else {
+					out.Children = (out.Children)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 Forum
+					(v4).UnmarshalEasyJSON(in)
+					out.Children = append(out.Children, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -58,7 +159,7 @@ func easyjsonC8d74561DecodeForumAppInternalForumappModels(in *jlexer.Lexer, out
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeForumAppInternalForumappModels(out *jwriter.Writer, in Forum) {
+func easyjsonC8d74561EncodeForumAppInternalForumappModels1(out *jwriter.Writer, in Forum) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -98,29 +199,73 @@ func easyjsonC8d74561EncodeForumAppInternalForumappModels(out *jwriter.Writer, i
 		out.RawString(prefix)
 		out.Int32(int32(in.Threads))
 	}
+	if in.Description != "" {
+		const prefix string = ",\"description\":"
+		out.RawString(prefix)
+		out.String(string(in.Description))
+	}
+	if in.Parent != "" {
+		const prefix string = ",\"parent\":"
+		out.RawString(prefix)
+		out.String(string(in.Parent))
+	}
+	if in.Position != 0 {
+		const prefix string = ",\"position\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Position))
+	}
+	if in.Category {
+		const prefix string = ",\"category\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Category))
+	}
+	if in.TotalPosts != 0 {
+		const prefix string = ",\"total_posts\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.TotalPosts))
+	}
+	if in.TotalThreads != 0 {
+		const prefix string = ",\"total_threads\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.TotalThreads))
+	}
+	if len(in.Children) != 0 {
+		const prefix string = ",\"children\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v5, v6 := range in.Children {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				(v6).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
 func (v Forum) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeForumAppInternalForumappModels(&w, v)
+	easyjsonC8d74561EncodeForumAppInternalForumappModels1(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Forum) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeForumAppInternalForumappModels(w, v)
+	easyjsonC8d74561EncodeForumAppInternalForumappModels1(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Forum) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeForumAppInternalForumappModels(&r, v)
+	easyjsonC8d74561DecodeForumAppInternalForumappModels1(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Forum) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeForumAppInternalForumappModels(l, v)
+	easyjsonC8d74561DecodeForumAppInternalForumappModels1(l, v)
 }
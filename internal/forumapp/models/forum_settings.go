@@ -0,0 +1,13 @@
+package models
+
+type ForumSettings struct {
+	Description    string   `json:"description"`
+	Rules          string   `json:"rules"`
+	ReadOnly       bool     `json:"read_only"`
+	AnonymousRead  bool     `json:"anonymous_read"`
+	MinAccountAge  int32    `json:"min_account_age"`
+	MaxPostLength  int32    `json:"max_post_length"`
+	AllowedTags    []string `json:"allowed_tags"`
+	AnonymousVotes bool     `json:"anonymous_votes"`
+	MinReputation  int32    `json:"min_reputation"`
+}
@@ -0,0 +1,173 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjsonDaa21071DecodeForumAppInternalForumappModels(in *jlexer.Lexer, out *ForumSettings) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "description":
+			out.Description = string(in.String())
+		case "rules":
+			out.Rules = string(in.String())
+		case "read_only":
+			out.ReadOnly = bool(in.Bool())
+		case "anonymous_read":
+			out.AnonymousRead = bool(in.Bool())
+		case "min_account_age":
+			out.MinAccountAge = int32(in.Int32())
+		case "max_post_length":
+			out.MaxPostLength = int32(in.Int32())
+		case "allowed_tags":
+			if in.IsNull() {
+				in.Skip()
+				out.AllowedTags = nil
+			} else {
+				in.Delim('[')
+				if out.AllowedTags == nil {
+					if !in.IsDelim(']') {
+						out.AllowedTags = make([]string, 0, 4)
+					} else {
+						out.AllowedTags = []string{}
+					}
+				} else {
+					out.AllowedTags = (out.AllowedTags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 string
+					v1 = string(in.String())
+					out.AllowedTags = append(out.AllowedTags, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "anonymous_votes":
+			out.AnonymousVotes = bool(in.Bool())
+		case "min_reputation":
+			out.MinReputation = int32(in.Int32())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonDaa21071EncodeForumAppInternalForumappModels(out *jwriter.Writer, in ForumSettings) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"description\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Description))
+	}
+	{
+		const prefix string = ",\"rules\":"
+		out.RawString(prefix)
+		out.String(string(in.Rules))
+	}
+	{
+		const prefix string = ",\"read_only\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.ReadOnly))
+	}
+	{
+		const prefix string = ",\"anonymous_read\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.AnonymousRead))
+	}
+	{
+		const prefix string = ",\"min_account_age\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.MinAccountAge))
+	}
+	{
+		const prefix string = ",\"max_post_length\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.MaxPostLength))
+	}
+	{
+		const prefix string = ",\"allowed_tags\":"
+		out.RawString(prefix)
+		if in.AllowedTags == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.AllowedTags {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v3))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"anonymous_votes\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.AnonymousVotes))
+	}
+	{
+		const prefix string = ",\"min_reputation\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.MinReputation))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForumSettings) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonDaa21071EncodeForumAppInternalForumappModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForumSettings) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonDaa21071EncodeForumAppInternalForumappModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForumSettings) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonDaa21071DecodeForumAppInternalForumappModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForumSettings) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonDaa21071DecodeForumAppInternalForumappModels(l, v)
+}
@@ -0,0 +1,16 @@
+package models
+
+// GatewayRequest is a message sent by a WebSocket gateway client.
+type GatewayRequest struct {
+	Action      string `json:"action"`
+	Channel     string `json:"channel"`
+	LastEventId int64  `json:"last_event_id,omitempty"`
+}
+
+// GatewayReply acknowledges a request or reports an error, events are sent
+// as they are.
+type GatewayReply struct {
+	Type    string `json:"type"`
+	Channel string `json:"channel,omitempty"`
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,179 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjsonAa2664a0DecodeForumAppInternalForumappModels(in *jlexer.Lexer, out *GatewayRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "action":
+			out.Action = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "last_event_id":
+			out.LastEventId = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonAa2664a0EncodeForumAppInternalForumappModels(out *jwriter.Writer, in GatewayRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"action\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Action))
+	}
+	{
+		const prefix string = ",\"channel\":"
+		out.RawString(prefix)
+		out.String(string(in.Channel))
+	}
+	if in.LastEventId != 0 {
+		const prefix string = ",\"last_event_id\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.LastEventId))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GatewayRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonAa2664a0EncodeForumAppInternalForumappModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GatewayRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonAa2664a0EncodeForumAppInternalForumappModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GatewayRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonAa2664a0DecodeForumAppInternalForumappModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GatewayRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonAa2664a0DecodeForumAppInternalForumappModels(l, v)
+}
+func easyjsonAa2664a0DecodeForumAppInternalForumappModels1(in *jlexer.Lexer, out *GatewayReply) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = string(in.String())
+		case "channel":
+			out.Channel = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonAa2664a0EncodeForumAppInternalForumappModels1(out *jwriter.Writer, in GatewayReply) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	if in.Channel != "" {
+		const prefix string = ",\"channel\":"
+		out.RawString(prefix)
+		out.String(string(in.Channel))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GatewayReply) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonAa2664a0EncodeForumAppInternalForumappModels1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GatewayReply) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonAa2664a0EncodeForumAppInternalForumappModels1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GatewayReply) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonAa2664a0DecodeForumAppInternalForumappModels1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GatewayReply) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonAa2664a0DecodeForumAppInternalForumappModels1(l, v)
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+const (
+	NotificationReply      = "reply"
+	NotificationThreadPost = "thread_post"
+	NotificationVote       = "vote"
+	NotificationMention    = "mention"
+)
+
+type Notification struct {
+	Id      int64     `json:"id"`
+	Type    string    `json:"type"`
+	Actor   string    `json:"actor"`
+	Thread  int64     `json:"thread,omitempty"`
+	Post    int64     `json:"post,omitempty"`
+	Read    bool      `json:"read"`
+	Created time.Time `json:"created"`
+}
+
+//easyjson:json
+type Notifications []Notification
+
+type NotificationCount struct {
+	Unread int32 `json:"unread"`
+}
+
+// NotificationsRead lists the notifications to mark as read, all of them
+// when Ids is empty.
+type NotificationsRead struct {
+	Ids []int64 `json:"ids,omitempty"`
+}
+
+type NotificationPreferences struct {
+	Reply      bool `json:"reply"`
+	ThreadPost bool `json:"thread_post"`
+	Vote       bool `json:"vote"`
+	Mention    bool `json:"mention"`
+}
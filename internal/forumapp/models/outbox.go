@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mailru/easyjson"
+)
+
+const (
+	OutboxThreadCreated = "thread.created"
+	OutboxPostCreated   = "post.created"
+	OutboxPostDeleted   = "post.deleted"
+	OutboxVoteCast      = "vote.cast"
+	OutboxVoteRetracted = "vote.retracted"
+	OutboxUserUpdated   = "user.updated"
+
+	OutboxPostVoteCast        = "post.vote.cast"
+	OutboxPostVoteRetracted   = "post.vote.retracted"
+	OutboxPostReactionAdded   = "post.reaction.added"
+	OutboxPostReactionRemoved = "post.reaction.removed"
+)
+
+// OutboxEvent is a domain event stored in the same transaction as the change
+// it describes. PublishedTo lists the sinks that already accepted it, so that
+// a retry only goes to the ones that failed.
+type OutboxEvent struct {
+	Id          int64               `json:"id"`
+	Aggregate   string              `json:"aggregate"`
+	AggregateId string              `json:"aggregateId"`
+	Type        string              `json:"type"`
+	Payload     easyjson.RawMessage `json:"payload"`
+	Created     time.Time           `json:"created"`
+	Attempts    int32               `json:"-"`
+	PublishedTo []string            `json:"-"`
+}
+
+//easyjson:json
+type OutboxEvents []OutboxEvent
+
+type VoteCast struct {
+	Thread int64 `json:"thread"`
+	User   int64 `json:"user"`
+	Voice  int32 `json:"voice"`
+}
+
+type PostVoteCast struct {
+	Post  int64 `json:"post"`
+	User  int64 `json:"user"`
+	Voice int32 `json:"voice"`
+}
+
+type PostReaction struct {
+	Post  int64  `json:"post"`
+	User  int64  `json:"user"`
+	Emoji string `json:"emoji"`
+}
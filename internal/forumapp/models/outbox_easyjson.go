@@ -0,0 +1,428 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson97b5aa9fDecodeForumAppInternalForumappModels(in *jlexer.Lexer, out *VoteCast) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "thread":
+			out.Thread = int64(in.Int64())
+		case "user":
+			out.User = int64(in.Int64())
+		case "voice":
+			out.Voice = int32(in.Int32())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson97b5aa9fEncodeForumAppInternalForumappModels(out *jwriter.Writer, in VoteCast) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.Thread))
+	}
+	{
+		const prefix string = ",\"user\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.User))
+	}
+	{
+		const prefix string = ",\"voice\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Voice))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v VoteCast) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson97b5aa9fEncodeForumAppInternalForumappModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v VoteCast) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson97b5aa9fEncodeForumAppInternalForumappModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *VoteCast) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson97b5aa9fDecodeForumAppInternalForumappModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *VoteCast) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson97b5aa9fDecodeForumAppInternalForumappModels(l, v)
+}
+func easyjson97b5aa9fDecodeForumAppInternalForumappModels1(in *jlexer.Lexer, out *PostVoteCast) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "post":
+			out.Post = int64(in.Int64())
+		case "user":
+			out.User = int64(in.Int64())
+		case "voice":
+			out.Voice = int32(in.Int32())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson97b5aa9fEncodeForumAppInternalForumappModels1(out *jwriter.Writer, in PostVoteCast) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.Post))
+	}
+	{
+		const prefix string = ",\"user\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.User))
+	}
+	{
+		const prefix string = ",\"voice\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Voice))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostVoteCast) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson97b5aa9fEncodeForumAppInternalForumappModels1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostVoteCast) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson97b5aa9fEncodeForumAppInternalForumappModels1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostVoteCast) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson97b5aa9fDecodeForumAppInternalForumappModels1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostVoteCast) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson97b5aa9fDecodeForumAppInternalForumappModels1(l, v)
+}
+func easyjson97b5aa9fDecodeForumAppInternalForumappModels2(in *jlexer.Lexer, out *PostReaction) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "post":
+			out.Post = int64(in.Int64())
+		case "user":
+			out.User = int64(in.Int64())
+		case "emoji":
+			out.Emoji = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson97b5aa9fEncodeForumAppInternalForumappModels2(out *jwriter.Writer, in PostReaction) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.Post))
+	}
+	{
+		const prefix string = ",\"user\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.User))
+	}
+	{
+		const prefix string = ",\"emoji\":"
+		out.RawString(prefix)
+		out.String(string(in.Emoji))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostReaction) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson97b5aa9fEncodeForumAppInternalForumappModels2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostReaction) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson97b5aa9fEncodeForumAppInternalForumappModels2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostReaction) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson97b5aa9fDecodeForumAppInternalForumappModels2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostReaction) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson97b5aa9fDecodeForumAppInternalForumappModels2(l, v)
+}
+func easyjson97b5aa9fDecodeForumAppInternalForumappModels3(in *jlexer.Lexer, out *OutboxEvents) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(OutboxEvents, 0, 0)
+			} else {
+				*out = OutboxEvents{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v1 OutboxEvent
+			(v1).UnmarshalEasyJSON(in)
+			*out = append(*out, v1)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson97b5aa9fEncodeForumAppInternalForumappModels3(out *jwriter.Writer, in OutboxEvents) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v2, v3 := range in {
+			if v2 > 0 {
+				out.RawByte(',')
+			}
+			(v3).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OutboxEvents) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson97b5aa9fEncodeForumAppInternalForumappModels3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OutboxEvents) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson97b5aa9fEncodeForumAppInternalForumappModels3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OutboxEvents) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson97b5aa9fDecodeForumAppInternalForumappModels3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OutboxEvents) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson97b5aa9fDecodeForumAppInternalForumappModels3(l, v)
+}
+func easyjson97b5aa9fDecodeForumAppInternalForumappModels4(in *jlexer.Lexer, out *OutboxEvent) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.Id = int64(in.Int64())
+		case "aggregate":
+			out.Aggregate = string(in.String())
+		case "aggregateId":
+			out.AggregateId = string(in.String())
+		case "type":
+			out.Type = string(in.String())
+		case "payload":
+			(out.Payload).UnmarshalEasyJSON(in)
+		case "created":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Created).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson97b5aa9fEncodeForumAppInternalForumappModels4(out *jwriter.Writer, in OutboxEvent) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.Id))
+	}
+	{
+		const prefix string = ",\"aggregate\":"
+		out.RawString(prefix)
+		out.String(string(in.Aggregate))
+	}
+	{
+		const prefix string = ",\"aggregateId\":"
+		out.RawString(prefix)
+		out.String(string(in.AggregateId))
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"payload\":"
+		out.RawString(prefix)
+		(in.Payload).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"created\":"
+		out.RawString(prefix)
+		out.Raw((in.Created).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OutboxEvent) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson97b5aa9fEncodeForumAppInternalForumappModels4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OutboxEvent) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson97b5aa9fEncodeForumAppInternalForumappModels4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OutboxEvent) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson97b5aa9fDecodeForumAppInternalForumappModels4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OutboxEvent) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson97b5aa9fDecodeForumAppInternalForumappModels4(l, v)
+}
@@ -3,15 +3,17 @@ package models
 import "time"
 
 type Post struct {
-	Id       int64     `json:"id,omitempty"`
-	Parent   int64     `json:"parent,omitempty"`
-	Author   string    `json:"author"`
-	Message  string    `json:"message"`
-	IsEdited bool      `json:"isEdited,omitempty"`
-	Forum    string    `json:"forum,omitempty"`
-	Thread   int32     `json:"thread,omitempty"`
-	Created  time.Time `json:"created,omitempty"`
-	Path     []int64   `json:"-"`
+	Id        int64            `json:"id,omitempty"`
+	Parent    int64            `json:"parent,omitempty"`
+	Author    string           `json:"author"`
+	Message   string           `json:"message"`
+	IsEdited  bool             `json:"isEdited,omitempty"`
+	Forum     string           `json:"forum,omitempty"`
+	Thread    int32            `json:"thread,omitempty"`
+	Created   time.Time        `json:"created,omitempty"`
+	Votes     int32            `json:"votes"`
+	Reactions map[string]int32 `json:"reactions,omitempty"`
+	Path      []int64          `json:"-"`
 }
 
 type PostFull struct {
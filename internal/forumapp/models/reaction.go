@@ -0,0 +1,11 @@
+package models
+
+// ReactionGroup lists who reacted to a post with the emoji, earliest first.
+type ReactionGroup struct {
+	Emoji string   `json:"emoji"`
+	Count int32    `json:"count"`
+	Users []string `json:"users"`
+}
+
+//easyjson:json
+type ReactionGroups []ReactionGroup
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"context"
+	"time"
+)
+
 type ForumRepository interface {
 	FindUserByNickname(nickname string) (User, error)
 	FindUsersByEmailOrNickname(email string, nickname string) ([]User, error)
@@ -8,21 +13,86 @@ type ForumRepository interface {
 
 	CreateForum(forumData Forum) (Forum, error)
 	FindForumBySlug(slug string) (Forum, error)
+	FindForums(limit string, since string, sort string, desc string, comparisonSign string) ([]Forum, error)
+	FindForumTree() ([]Forum, error)
+	IsForumInSubtree(rootSlug string, slug string) (bool, error)
+	GetForumSettings(forumId int64) (ForumSettings, error)
+	UpdateForumSettings(forumId int64, settings ForumSettings) error
+	UpdateForum(forumData Forum) (Forum, error)
+	DeleteForum(forumData Forum) error
 
 	CreateThread(threadData Thread) (Thread, error)
 	FindThreadBySlug(slug string) (Thread, error)
-	FindThreadsBySlugWithParams(slug string, limit string, since string, desc string, comparisonSign string) ([]Thread, error)
+	FindThreadsBySlugWithParams(slug string, limit string, since string, sort string, desc string, comparisonSign string, withSubforums bool, tags []string, matchAllTags bool) ([]Thread, error)
 	FindThreadBySlugOrId(id int64, slug string) (Thread, error)
-	CreatePosts(posts []Post, thread Thread) ([]Post, error)
-	FindVote(userId int64, threadId int64) error
-	UpdateVoteThread(userId int64, threadId int64, voice int32) error
-	AddVoteThread(userId int64, threadId int64, voice int32) error
-	GetPosts(threadId int64, limit string, since string, sort string, desc string, comparisonSign string) ([]Post, error)
+	FindPinnedThreads(slug string, withSubforums bool) ([]Thread, error)
+	CountPinnedThreads(slug string) (int, int, error)
+	GetForumTags(slug string, limit int) ([]TagCount, error)
+	Search(filter SearchFilter) ([]SearchResult, error)
+	PinThread(threadId int64, pinData ThreadPin) (Thread, error)
+	MoveThread(thread Thread, from Forum, to Forum, redirect bool) (Thread, error)
+	MergeThreads(source Thread, target Thread, sourceForum Forum, targetForum Forum) (Thread, error)
+	SplitThread(post Post, threadData Thread) (Thread, error)
+	CreatePosts(posts []Post, thread Thread, mentions [][]string) ([]Post, error)
+	VoteThread(userId int64, threadId int64, voice int32) error
+	CountThreadVotes(threadId int64) (int32, int32, error)
+	GetUserReputation(userId int64) ([]ForumReputation, error)
+	GetForumDailyStats(forumId int64, days int) ([]DailyStats, error)
+	GetTopPosters(forumId int64, limit int) ([]UserStats, error)
+	GetTopThreads(forumId int64, days int, limit int) ([]Thread, error)
+	CountActiveUsers(forumId int64) (ActiveUsers, error)
+	GetLeaderboard(sort string, limit int) ([]UserStats, error)
+	RefreshTrending(window string, period time.Duration) error
+	GetTrending(filter TrendingFilter) ([]TrendingThread, error)
+	GetThreadVoters(threadId int64, limit string, since string, desc string, comparisonSign string) ([]Voter, error)
+	GetUserVotes(userId int64, limit string, since string, desc string, comparisonSign string) ([]UserVote, error)
+	VotePost(userId int64, postId int64, voice int32) error
+	AddPostReaction(userId int64, postId int64, emoji string) error
+	DeletePostReaction(userId int64, postId int64, emoji string) error
+	GetPostReactions(postId int64) ([]ReactionGroup, error)
+	GetPosts(threadId int64, limit string, since string, sort string, desc string, comparisonSign string, startId int64) ([]Post, error)
 	UpdateThread(threadId int64, threadData Thread) (Thread, error)
 	GetForumUsers(forumId int64, limit string, since string, desc string, comparisonSign string) ([]User, error)
+	SearchUsers(query string, forumId int64, limit int, offset int) ([]User, error)
+	GetNotifications(userId int64, limit string, since string, unreadOnly bool) ([]Notification, error)
+	CountUnreadNotifications(userId int64) (int32, error)
+	MarkNotificationsRead(userId int64, ids []int64) error
+	GetNotificationPreferences(userId int64) (NotificationPreferences, error)
+	UpdateNotificationPreferences(userId int64, preferences NotificationPreferences) error
+	SubscribeThread(nicknames []string, threadId int64) error
+	UnsubscribeThread(userId int64, threadId int64) error
+	IsThreadSubscribed(nickname string, threadId int64) (bool, error)
+	SubscribeForum(userId int64, forumId int64) error
+	UnsubscribeForum(userId int64, forumId int64) error
+	GetFeedCursor(userId int64) (int64, error)
+	UpdateFeedCursor(userId int64, lastPostId int64) error
+	GetFeed(user User, since int64, limit int) ([]Post, error)
+	GetThreadRead(userId int64, threadId int64) (int64, []int64, error)
+	MarkThreadRead(userId int64, threadId int64, lastPostId int64, readPostIds []int64) (int64, error)
+	CountUnreadPosts(userId int64, threadIds []int64) (map[int64]int32, error)
+	FindFirstUnreadPost(userId int64, threadId int64, lastPostId int64, sort string) (int64, error)
+	FindFirstUnlistedPost(threadId int64, lastPostId int64, postIds []int64) (int64, error)
+	GetLastStreamEventId() (int64, error)
+	GetStreamEvents(channel string, afterId int64, limit int) ([]StreamEvent, error)
+	DeleteStreamEvents(before time.Time) error
+	ListenStreamEvents(ctx context.Context, wakeups chan<- struct{}) error
+	CreateWebhook(forumId int64, webhookData Webhook) (Webhook, error)
+	FindWebhooks(forumId int64) ([]Webhook, error)
+	FindWebhook(id int64, forumId int64) (Webhook, error)
+	DeleteWebhook(id int64) error
+	GetWebhookDeliveries(webhookId int64, limit string, since string) ([]WebhookDelivery, error)
+	RedeliverWebhookDelivery(webhookId int64, deliveryId int64) (WebhookDelivery, error)
+	ClaimWebhookDeliveries(limit int, lease time.Duration) ([]WebhookJob, error)
+	CompleteWebhookDelivery(delivery WebhookDelivery) error
+	ClaimOutboxEvents(limit int, lease time.Duration) ([]OutboxEvent, error)
+	MarkOutboxEventPublished(id int64, publishedTo []string) error
+	RetryOutboxEvent(id int64, publishedTo []string, retryAt time.Time, reason string) error
+	DeleteOutboxEvents(before time.Time) error
+	GetUserMentions(userId int64, limit string, since string, desc string, comparisonSign string, anonymousOnly bool) ([]Post, error)
 	GetPostInfo(postId int64, withUser bool, withForum bool, withThread bool) (PostFull, error)
 	FindPost(postId int64) (Post, error)
-	UpdatePost(postData Post) (Post, error)
+	UpdatePost(postData Post, mentions []string) (Post, error)
+	DeletePost(postData Post) (Thread, error)
 	ServiceStatus() (Status, error)
 	ServiceClear() error
 }
@@ -0,0 +1,8 @@
+package models
+
+// ForumReputation is the reputation a user earned in a forum, with the votes
+// decayed by their age.
+type ForumReputation struct {
+	Forum      string `json:"forum"`
+	Reputation int32  `json:"reputation"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type SearchResult struct {
+	Type    string    `json:"type"`
+	Id      int64     `json:"id"`
+	Thread  int64     `json:"thread"`
+	Forum   string    `json:"forum"`
+	Author  string    `json:"author"`
+	Title   string    `json:"title"`
+	Snippet string    `json:"snippet"`
+	Rank    float32   `json:"rank"`
+	Created time.Time `json:"created"`
+}
+
+//easyjson:json
+type SearchResults []SearchResult
+
+// SearchFilter narrows a full-text search. Zero values mean no filtering.
+type SearchFilter struct {
+	Query         string
+	Type          string
+	Forum         string
+	Author        string
+	Thread        int64
+	Since         time.Time
+	Until         time.Time
+	AnonymousOnly bool
+	Limit         int
+	Offset        int
+}
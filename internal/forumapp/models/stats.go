@@ -0,0 +1,34 @@
+package models
+
+type DailyStats struct {
+	Day     string `json:"day"`
+	Posts   int32  `json:"posts"`
+	Threads int32  `json:"threads"`
+}
+
+type UserStats struct {
+	Nickname string `json:"nickname"`
+	Posts    int32  `json:"posts"`
+	Threads  int32  `json:"threads"`
+}
+
+//easyjson:json
+type Leaderboard []UserStats
+
+// ActiveUsers counts the users who created threads or posts in the last day,
+// week and month.
+type ActiveUsers struct {
+	Day   int32 `json:"day"`
+	Week  int32 `json:"week"`
+	Month int32 `json:"month"`
+}
+
+type ForumStats struct {
+	Forum       string       `json:"forum"`
+	Posts       int64        `json:"posts"`
+	Threads     int32        `json:"threads"`
+	Daily       []DailyStats `json:"daily"`
+	TopPosters  []UserStats  `json:"top_posters"`
+	TopThreads  []Thread     `json:"top_threads"`
+	ActiveUsers ActiveUsers  `json:"active_users"`
+}
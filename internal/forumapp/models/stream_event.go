@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mailru/easyjson"
+)
+
+type StreamEvent struct {
+	Id      int64               `json:"id"`
+	Channel string              `json:"channel"`
+	Type    string              `json:"type"`
+	Payload easyjson.RawMessage `json:"payload"`
+	Created time.Time           `json:"created"`
+}
+
+//easyjson:json
+type StreamEvents []StreamEvent
+
+// EventStream is a subscription to the events of a channel: the stored events
+// the client missed followed by the live ones. Close must be called when the
+// client goes away; Events is closed when the subscriber falls behind.
+//
+//easyjson:skip
+type EventStream struct {
+	Channel string
+	Backlog []StreamEvent
+	Events  <-chan StreamEvent
+	Close   func()
+}
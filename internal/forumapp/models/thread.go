@@ -3,15 +3,61 @@ package models
 import "time"
 
 type Thread struct {
-	Id      int64     `json:"id,omitempty"`
-	Title   string    `json:"title"`
-	Author  string    `json:"author"`
-	Forum   string    `json:"forum,omitempty"`
-	Message string    `json:"message"`
-	Votes   int32     `json:"votes,omitempty"`
-	Slug    string    `json:"slug,omitempty"`
-	Created time.Time `json:"created,omitempty"`
+	Id             int64     `json:"id,omitempty"`
+	Title          string    `json:"title"`
+	Author         string    `json:"author"`
+	Forum          string    `json:"forum,omitempty"`
+	Message        string    `json:"message"`
+	Votes          int32     `json:"votes,omitempty"`
+	Slug           string    `json:"slug,omitempty"`
+	Created        time.Time `json:"created,omitempty"`
+	Pinned         bool      `json:"pinned,omitempty"`
+	PinOrder       int32     `json:"pin_order,omitempty"`
+	Announcement   bool      `json:"announcement,omitempty"`
+	Posts          int32     `json:"posts,omitempty"`
+	LastPostId     int64     `json:"last_post_id,omitempty"`
+	LastPostAuthor string    `json:"last_post_author,omitempty"`
+	LastPostAt     time.Time `json:"last_post_at,omitempty"`
+	MovedTo        int64     `json:"moved_to,omitempty"`
+	Tags           []string  `json:"tags,omitempty"`
+	Subscribed     *bool     `json:"subscribed,omitempty"`
+	Unread         *int32    `json:"unread,omitempty"`
+}
+
+type ThreadPin struct {
+	Pinned       bool  `json:"pinned"`
+	Order        int32 `json:"order,omitempty"`
+	Announcement bool  `json:"announcement"`
+}
+
+type ThreadMove struct {
+	Forum    string `json:"forum"`
+	Redirect bool   `json:"redirect,omitempty"`
+}
+
+type ThreadMerge struct {
+	Target string `json:"target"`
+}
+
+type ThreadSplit struct {
+	Title string `json:"title"`
+	Slug  string `json:"slug,omitempty"`
 }
 
 //easyjson:json
 type Threads []Thread
+
+// ThreadRead is the read marker of a user in a thread: the last read post and
+// the number of posts after it.
+type ThreadRead struct {
+	Post   int64 `json:"post"`
+	Unread int32 `json:"unread"`
+}
+
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int32  `json:"count"`
+}
+
+//easyjson:json
+type TagCounts []TagCount
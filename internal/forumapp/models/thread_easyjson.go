@@ -83,7 +83,372 @@ func (v *Threads) UnmarshalJSON(data []byte) error {
 func (v *Threads) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson2d00218DecodeForumAppInternalForumappModels(l, v)
 }
-func easyjson2d00218DecodeForumAppInternalForumappModels1(in *jlexer.Lexer, out *Thread) {
+func easyjson2d00218DecodeForumAppInternalForumappModels1(in *jlexer.Lexer, out *ThreadSplit) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "title":
+			out.Title = string(in.String())
+		case "slug":
+			out.Slug = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2d00218EncodeForumAppInternalForumappModels1(out *jwriter.Writer, in ThreadSplit) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Title))
+	}
+	if in.Slug != "" {
+		const prefix string = ",\"slug\":"
+		out.RawString(prefix)
+		out.String(string(in.Slug))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ThreadSplit) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson2d00218EncodeForumAppInternalForumappModels1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ThreadSplit) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson2d00218EncodeForumAppInternalForumappModels1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ThreadSplit) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson2d00218DecodeForumAppInternalForumappModels1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ThreadSplit) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson2d00218DecodeForumAppInternalForumappModels1(l, v)
+}
+func easyjson2d00218DecodeForumAppInternalForumappModels2(in *jlexer.Lexer, out *ThreadRead) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "post":
+			out.Post = int64(in.Int64())
+		case "unread":
+			out.Unread = int32(in.Int32())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2d00218EncodeForumAppInternalForumappModels2(out *jwriter.Writer, in ThreadRead) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.Post))
+	}
+	{
+		const prefix string = ",\"unread\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Unread))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ThreadRead) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson2d00218EncodeForumAppInternalForumappModels2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ThreadRead) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson2d00218EncodeForumAppInternalForumappModels2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ThreadRead) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson2d00218DecodeForumAppInternalForumappModels2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ThreadRead) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson2d00218DecodeForumAppInternalForumappModels2(l, v)
+}
+func easyjson2d00218DecodeForumAppInternalForumappModels3(in *jlexer.Lexer, out *ThreadPin) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "pinned":
+			out.Pinned = bool(in.Bool())
+		case "order":
+			out.Order = int32(in.Int32())
+		case "announcement":
+			out.Announcement = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2d00218EncodeForumAppInternalForumappModels3(out *jwriter.Writer, in ThreadPin) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"pinned\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.Pinned))
+	}
+	if in.Order != 0 {
+		const prefix string = ",\"order\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Order))
+	}
+	{
+		const prefix string = ",\"announcement\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Announcement))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ThreadPin) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson2d00218EncodeForumAppInternalForumappModels3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ThreadPin) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson2d00218EncodeForumAppInternalForumappModels3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ThreadPin) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson2d00218DecodeForumAppInternalForumappModels3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ThreadPin) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson2d00218DecodeForumAppInternalForumappModels3(l, v)
+}
+func easyjson2d00218DecodeForumAppInternalForumappModels4(in *jlexer.Lexer, out *ThreadMove) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "forum":
+			out.Forum = string(in.String())
+		case "redirect":
+			out.Redirect = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2d00218EncodeForumAppInternalForumappModels4(out *jwriter.Writer, in ThreadMove) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"forum\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Forum))
+	}
+	if in.Redirect {
+		const prefix string = ",\"redirect\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Redirect))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ThreadMove) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson2d00218EncodeForumAppInternalForumappModels4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ThreadMove) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson2d00218EncodeForumAppInternalForumappModels4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ThreadMove) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson2d00218DecodeForumAppInternalForumappModels4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ThreadMove) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson2d00218DecodeForumAppInternalForumappModels4(l, v)
+}
+func easyjson2d00218DecodeForumAppInternalForumappModels5(in *jlexer.Lexer, out *ThreadMerge) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "target":
+			out.Target = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2d00218EncodeForumAppInternalForumappModels5(out *jwriter.Writer, in ThreadMerge) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"target\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Target))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ThreadMerge) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson2d00218EncodeForumAppInternalForumappModels5(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ThreadMerge) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson2d00218EncodeForumAppInternalForumappModels5(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ThreadMerge) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson2d00218DecodeForumAppInternalForumappModels5(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ThreadMerge) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson2d00218DecodeForumAppInternalForumappModels5(l, v)
+}
+func easyjson2d00218DecodeForumAppInternalForumappModels6(in *jlexer.Lexer, out *Thread) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -120,6 +485,67 @@ func easyjson2d00218DecodeForumAppInternalForumappModels1(in *jlexer.Lexer, out
 			if data := in.Raw(); in.Ok() {
 				in.AddError((out.Created).UnmarshalJSON(data))
 			}
+		case "pinned":
+			out.Pinned = bool(in.Bool())
+		case "pin_order":
+			out.PinOrder = int32(in.Int32())
+		case "announcement":
+			out.Announcement = bool(in.Bool())
+		case "posts":
+			out.Posts = int32(in.Int32())
+		case "last_post_id":
+			out.LastPostId = int64(in.Int64())
+		case "last_post_author":
+			out.LastPostAuthor = string(in.String())
+		case "last_post_at":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.LastPostAt).UnmarshalJSON(data))
+			}
+		case "moved_to":
+			out.MovedTo = int64(in.Int64())
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 string
+					v4 = string(in.String())
+					out.Tags = append(out.Tags, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "subscribed":
+			if in.IsNull() {
+				in.Skip()
+				out.Subscribed = nil
+			} else {
+				if out.Subscribed == nil {
+					out.Subscribed = new(bool)
+				}
+				*out.Subscribed = bool(in.Bool())
+			}
+		case "unread":
+			if in.IsNull() {
+				in.Skip()
+				out.Unread = nil
+			} else {
+				if out.Unread == nil {
+					out.Unread = new(int32)
+				}
+				*out.Unread = int32(in.Int32())
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -130,7 +556,7 @@ func easyjson2d00218DecodeForumAppInternalForumappModels1(in *jlexer.Lexer, out
 		in.Consumed()
 	}
 }
-func easyjson2d00218EncodeForumAppInternalForumappModels1(out *jwriter.Writer, in Thread) {
+func easyjson2d00218EncodeForumAppInternalForumappModels6(out *jwriter.Writer, in Thread) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -180,29 +606,232 @@ func easyjson2d00218EncodeForumAppInternalForumappModels1(out *jwriter.Writer, i
 		out.RawString(prefix)
 		out.Raw((in.Created).MarshalJSON())
 	}
+	if in.Pinned {
+		const prefix string = ",\"pinned\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Pinned))
+	}
+	if in.PinOrder != 0 {
+		const prefix string = ",\"pin_order\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.PinOrder))
+	}
+	if in.Announcement {
+		const prefix string = ",\"announcement\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Announcement))
+	}
+	if in.Posts != 0 {
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Posts))
+	}
+	if in.LastPostId != 0 {
+		const prefix string = ",\"last_post_id\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.LastPostId))
+	}
+	if in.LastPostAuthor != "" {
+		const prefix string = ",\"last_post_author\":"
+		out.RawString(prefix)
+		out.String(string(in.LastPostAuthor))
+	}
+	if true {
+		const prefix string = ",\"last_post_at\":"
+		out.RawString(prefix)
+		out.Raw((in.LastPostAt).MarshalJSON())
+	}
+	if in.MovedTo != 0 {
+		const prefix string = ",\"moved_to\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.MovedTo))
+	}
+	if len(in.Tags) != 0 {
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v5, v6 := range in.Tags {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v6))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Subscribed != nil {
+		const prefix string = ",\"subscribed\":"
+		out.RawString(prefix)
+		out.Bool(bool(*in.Subscribed))
+	}
+	if in.Unread != nil {
+		const prefix string = ",\"unread\":"
+		out.RawString(prefix)
+		out.Int32(int32(*in.Unread))
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
 func (v Thread) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjson2d00218EncodeForumAppInternalForumappModels1(&w, v)
+	easyjson2d00218EncodeForumAppInternalForumappModels6(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Thread) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson2d00218EncodeForumAppInternalForumappModels1(w, v)
+	easyjson2d00218EncodeForumAppInternalForumappModels6(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Thread) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjson2d00218DecodeForumAppInternalForumappModels1(&r, v)
+	easyjson2d00218DecodeForumAppInternalForumappModels6(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Thread) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson2d00218DecodeForumAppInternalForumappModels1(l, v)
+	easyjson2d00218DecodeForumAppInternalForumappModels6(l, v)
+}
+func easyjson2d00218DecodeForumAppInternalForumappModels7(in *jlexer.Lexer, out *TagCounts) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(TagCounts, 0, 2)
+			} else {
+				*out = TagCounts{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v7 TagCount
+			(v7).UnmarshalEasyJSON(in)
+			*out = append(*out, v7)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2d00218EncodeForumAppInternalForumappModels7(out *jwriter.Writer, in TagCounts) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v8, v9 := range in {
+			if v8 > 0 {
+				out.RawByte(',')
+			}
+			(v9).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TagCounts) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson2d00218EncodeForumAppInternalForumappModels7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TagCounts) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson2d00218EncodeForumAppInternalForumappModels7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TagCounts) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson2d00218DecodeForumAppInternalForumappModels7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TagCounts) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson2d00218DecodeForumAppInternalForumappModels7(l, v)
+}
+func easyjson2d00218DecodeForumAppInternalForumappModels8(in *jlexer.Lexer, out *TagCount) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "tag":
+			out.Tag = string(in.String())
+		case "count":
+			out.Count = int32(in.Int32())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2d00218EncodeForumAppInternalForumappModels8(out *jwriter.Writer, in TagCount) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"tag\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Tag))
+	}
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Count))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TagCount) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson2d00218EncodeForumAppInternalForumappModels8(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TagCount) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson2d00218EncodeForumAppInternalForumappModels8(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TagCount) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson2d00218DecodeForumAppInternalForumappModels8(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TagCount) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson2d00218DecodeForumAppInternalForumappModels8(l, v)
 }
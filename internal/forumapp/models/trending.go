@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type TrendingThread struct {
+	Thread   Thread    `json:"thread"`
+	Score    float64   `json:"score"`
+	Posts    int32     `json:"recent_posts"`
+	Votes    int32     `json:"recent_votes"`
+	Computed time.Time `json:"computed"`
+}
+
+//easyjson:json
+type TrendingThreads []TrendingThread
+
+//easyjson:skip
+type TrendingFilter struct {
+	Window        string
+	Forum         string
+	Tags          []string
+	MatchAllTags  bool
+	AnonymousOnly bool
+	Limit         int
+}
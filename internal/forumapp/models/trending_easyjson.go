@@ -0,0 +1,181 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson19a0b453DecodeForumAppInternalForumappModels(in *jlexer.Lexer, out *TrendingThreads) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(TrendingThreads, 0, 0)
+			} else {
+				*out = TrendingThreads{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v1 TrendingThread
+			(v1).UnmarshalEasyJSON(in)
+			*out = append(*out, v1)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson19a0b453EncodeForumAppInternalForumappModels(out *jwriter.Writer, in TrendingThreads) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v2, v3 := range in {
+			if v2 > 0 {
+				out.RawByte(',')
+			}
+			(v3).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TrendingThreads) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson19a0b453EncodeForumAppInternalForumappModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TrendingThreads) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson19a0b453EncodeForumAppInternalForumappModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TrendingThreads) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson19a0b453DecodeForumAppInternalForumappModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TrendingThreads) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson19a0b453DecodeForumAppInternalForumappModels(l, v)
+}
+func easyjson19a0b453DecodeForumAppInternalForumappModels1(in *jlexer.Lexer, out *TrendingThread) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "thread":
+			(out.Thread).UnmarshalEasyJSON(in)
+		case "score":
+			out.Score = float64(in.Float64())
+		case "recent_posts":
+			out.Posts = int32(in.Int32())
+		case "recent_votes":
+			out.Votes = int32(in.Int32())
+		case "computed":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Computed).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson19a0b453EncodeForumAppInternalForumappModels1(out *jwriter.Writer, in TrendingThread) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix[1:])
+		(in.Thread).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"score\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Score))
+	}
+	{
+		const prefix string = ",\"recent_posts\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Posts))
+	}
+	{
+		const prefix string = ",\"recent_votes\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Votes))
+	}
+	{
+		const prefix string = ",\"computed\":"
+		out.RawString(prefix)
+		out.Raw((in.Computed).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TrendingThread) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson19a0b453EncodeForumAppInternalForumappModels1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TrendingThread) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson19a0b453EncodeForumAppInternalForumappModels1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TrendingThread) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson19a0b453DecodeForumAppInternalForumappModels1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TrendingThread) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson19a0b453DecodeForumAppInternalForumappModels1(l, v)
+}
@@ -6,19 +6,59 @@ type ForumUsecase interface {
 	UpdateUser(userData User) (User, int, error)
 
 	CreateForum(forumData Forum) (Forum, int, error)
-	GetForum(slug string) (Forum, int, error)
+	GetForum(slug string, actor string) (Forum, int, error)
+	GetForums(params map[string][]string) (Forums, int, error)
+	GetForumTree() (Forums, int, error)
+	UpdateForum(slug string, actor string, forumData Forum) (Forum, int, error)
+	DeleteForum(slug string, actor string) (int, error)
+	GetForumSettings(slug string, actor string) (ForumSettings, int, error)
+	UpdateForumSettings(slug string, actor string, settings ForumSettings) (ForumSettings, int, error)
 
 	CreateThread(slug string, threadData Thread) (Thread, int, error)
-	GetThreads(slug string, params map[string][]string) (Threads, int, error)
+	GetThreads(slug string, actor string, params map[string][]string) (Threads, int, error)
+	GetForumTags(slug string, actor string, params map[string][]string) (TagCounts, int, error)
+	Search(actor string, params map[string][]string) (SearchResults, int, error)
 
 	CreatesPosts(threadSlugOrId string, postsData []Post) (Posts, int, error)
 	VoteThread(threadSlugOrId string, voteData Vote) (Thread, int, error)
-	FindThreadBySlugOrId(threadSlugOrId string) (Thread, int, error)
-	GetPosts(threadSlugOrId string, params map[string][]string) (Posts, int, error)
+	GetThreadVotes(threadSlugOrId string, actor string, params map[string][]string) (ThreadVotes, int, error)
+	GetUserVotes(nickname string, actor string, params map[string][]string) (UserVotes, int, error)
+	GetForumStats(slug string, actor string, params map[string][]string) (ForumStats, int, error)
+	GetLeaderboard(params map[string][]string) (Leaderboard, int, error)
+	GetTrending(actor string, params map[string][]string) (TrendingThreads, int, error)
+	VotePost(id string, actor string, voteData Vote) (Post, int, error)
+	AddPostReaction(id string, emoji string, actor string) (Post, int, error)
+	DeletePostReaction(id string, emoji string, actor string) (Post, int, error)
+	GetPostReactions(id string, actor string) (ReactionGroups, int, error)
+	FindThreadBySlugOrId(threadSlugOrId string, actor string) (Thread, int, error)
+	GetPosts(threadSlugOrId string, actor string, params map[string][]string) (Posts, int, error)
 	UpdateThread(threadSlugOrId string, newThread Thread) (Thread, int, error)
-	GetForumUsers(forumSlug string, params map[string][]string) (Users, int, error)
-	GetPostInfo(id string, params map[string][]string) (PostFull, int, error)
+	PinThread(threadSlugOrId string, actor string, pinData ThreadPin) (Thread, int, error)
+	MoveThread(threadSlugOrId string, actor string, moveData ThreadMove) (Thread, int, error)
+	MergeThreads(threadSlugOrId string, actor string, mergeData ThreadMerge) (Thread, int, error)
+	SplitThread(postId string, actor string, splitData ThreadSplit) (Thread, int, error)
+	GetForumUsers(forumSlug string, actor string, params map[string][]string) (Users, int, error)
+	SearchUsers(actor string, params map[string][]string) (Users, int, error)
+	GetUserMentions(nickname string, actor string, params map[string][]string) (Posts, int, error)
+	GetNotifications(nickname string, actor string, params map[string][]string) (Notifications, int, error)
+	CountNotifications(nickname string, actor string) (NotificationCount, int, error)
+	MarkNotificationsRead(nickname string, actor string, read NotificationsRead) (NotificationCount, int, error)
+	GetNotificationPreferences(nickname string, actor string) (NotificationPreferences, int, error)
+	UpdateNotificationPreferences(nickname string, actor string, preferences NotificationPreferences) (NotificationPreferences, int, error)
+	SubscribeThread(threadSlugOrId string, actor string, subscribe bool) (Thread, int, error)
+	SubscribeForum(slug string, actor string, subscribe bool) (int, error)
+	GetFeed(nickname string, actor string, params map[string][]string) (Posts, int, error)
+	MarkThreadRead(threadSlugOrId string, actor string, readData ThreadRead) (ThreadRead, int, error)
+	StreamThreadEvents(threadSlugOrId string, actor string, lastEventId int64) (EventStream, int, error)
+	SubscribeChannel(channel string, actor string, lastEventId int64) (EventStream, int, error)
+	CreateWebhook(slug string, actor string, webhookData Webhook) (Webhook, int, error)
+	GetWebhooks(slug string, actor string) (Webhooks, int, error)
+	DeleteWebhook(slug string, id string, actor string) (int, error)
+	GetWebhookDeliveries(slug string, id string, actor string, params map[string][]string) (WebhookDeliveries, int, error)
+	RedeliverWebhook(slug string, id string, deliveryId string, actor string) (WebhookDelivery, int, error)
+	GetPostInfo(id string, actor string, params map[string][]string) (PostFull, int, error)
 	UpdatePost(id string, newPost Post) (Post, int, error)
+	DeletePost(id string, actor string) (Thread, int, error)
 	ServiceStatus() (Status, int, error)
 	ServiceClear() (int, error)
 }
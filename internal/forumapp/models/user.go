@@ -1,11 +1,18 @@
 package models
 
+import "time"
+
 type User struct {
 	Id       int64  `json:"id,omitempty"`
 	Nickname string `json:"nickname,omitempty"`
 	Fullname string `json:"fullname"`
 	About    string `json:"about,omitempty"`
 	Email    string `json:"email"`
+	IsAdmin  bool   `json:"-"`
+	// only the profile carries the reputation
+	Reputation      *int32            `json:"reputation,omitempty"`
+	ForumReputation []ForumReputation `json:"forum_reputation,omitempty"`
+	Created         time.Time         `json:"-"`
 }
 
 //easyjson:json
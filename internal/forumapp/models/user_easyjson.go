@@ -112,6 +112,39 @@ func easyjson9e1087fdDecodeForumAppInternalForumappModels1(in *jlexer.Lexer, out
 			out.About = string(in.String())
 		case "email":
 			out.Email = string(in.String())
+		case "reputation":
+			if in.IsNull() {
+				in.Skip()
+				out.Reputation = nil
+			} else {
+				if out.Reputation == nil {
+					out.Reputation = new(int32)
+				}
+				*out.Reputation = int32(in.Int32())
+			}
+		case "forum_reputation":
+			if in.IsNull() {
+				in.Skip()
+				out.ForumReputation = nil
+			} else {
+				in.Delim('[')
+				if out.ForumReputation == nil {
+					if !in.IsDelim(']') {
+						out.ForumReputation = make([]ForumReputation, 0, 2)
+					} else {
+						out.ForumReputation = []ForumReputation{}
+					}
+				} else {
+					out.ForumReputation = (out.ForumReputation)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 ForumReputation
+					easyjson9e1087fdDecodeForumAppInternalForumappModels2(in, &v4)
+					out.ForumReputation = append(out.ForumReputation, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -162,6 +195,25 @@ func easyjson9e1087fdEncodeForumAppInternalForumappModels1(out *jwriter.Writer,
 		out.RawString(prefix)
 		out.String(string(in.Email))
 	}
+	if in.Reputation != nil {
+		const prefix string = ",\"reputation\":"
+		out.RawString(prefix)
+		out.Int32(int32(*in.Reputation))
+	}
+	if len(in.ForumReputation) != 0 {
+		const prefix string = ",\"forum_reputation\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v5, v6 := range in.ForumReputation {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				easyjson9e1087fdEncodeForumAppInternalForumappModels2(out, v6)
+			}
+			out.RawByte(']')
+		}
+	}
 	out.RawByte('}')
 }
 
@@ -188,3 +240,52 @@ func (v *User) UnmarshalJSON(data []byte) error {
 func (v *User) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjson9e1087fdDecodeForumAppInternalForumappModels1(l, v)
 }
+func easyjson9e1087fdDecodeForumAppInternalForumappModels2(in *jlexer.Lexer, out *ForumReputation) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "forum":
+			out.Forum = string(in.String())
+		case "reputation":
+			out.Reputation = int32(in.Int32())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9e1087fdEncodeForumAppInternalForumappModels2(out *jwriter.Writer, in ForumReputation) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"forum\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Forum))
+	}
+	{
+		const prefix string = ",\"reputation\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Reputation))
+	}
+	out.RawByte('}')
+}
@@ -1,6 +1,37 @@
 package models
 
+import "time"
+
 type Vote struct {
 	Nickname string `json:"nickname"`
 	Voice    int32  `json:"voice"`
 }
+
+type Voter struct {
+	Id       int64     `json:"id"`
+	Nickname string    `json:"nickname"`
+	Voice    int32     `json:"voice"`
+	Created  time.Time `json:"created"`
+}
+
+// ThreadVotes is the breakdown of the votes of a thread. Voters is left empty
+// when the forum keeps votes anonymous.
+type ThreadVotes struct {
+	Up        int32   `json:"up"`
+	Down      int32   `json:"down"`
+	Anonymous bool    `json:"anonymous"`
+	Voters    []Voter `json:"voters"`
+}
+
+type UserVote struct {
+	Id      int64     `json:"id"`
+	Thread  int64     `json:"thread"`
+	Slug    string    `json:"slug,omitempty"`
+	Title   string    `json:"title"`
+	Forum   string    `json:"forum"`
+	Voice   int32     `json:"voice"`
+	Created time.Time `json:"created"`
+}
+
+//easyjson:json
+type UserVotes []UserVote
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mailru/easyjson"
+)
+
+const (
+	WebhookThreadCreated = "thread_created"
+	WebhookPostCreated   = "post_created"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+type Webhook struct {
+	Id      int64     `json:"id,omitempty"`
+	Forum   string    `json:"forum,omitempty"`
+	Url     string    `json:"url"`
+	Secret  string    `json:"secret,omitempty"`
+	Events  []string  `json:"events"`
+	Active  bool      `json:"active"`
+	Created time.Time `json:"created,omitempty"`
+}
+
+//easyjson:json
+type Webhooks []Webhook
+
+// WebhookEvent is the JSON body sent to the webhooks.
+type WebhookEvent struct {
+	Event  string  `json:"event"`
+	Forum  string  `json:"forum"`
+	Thread *Thread `json:"thread,omitempty"`
+	Post   *Post   `json:"post,omitempty"`
+}
+
+type WebhookDelivery struct {
+	Id          int64               `json:"id"`
+	Webhook     int64               `json:"webhook"`
+	Event       string              `json:"event"`
+	Payload     easyjson.RawMessage `json:"payload"`
+	Status      string              `json:"status"`
+	Attempts    int32               `json:"attempts"`
+	NextAttempt time.Time           `json:"next_attempt"`
+	StatusCode  int32               `json:"status_code,omitempty"`
+	Error       string              `json:"error,omitempty"`
+	Created     time.Time           `json:"created"`
+	DeliveredAt *time.Time          `json:"delivered_at,omitempty"`
+}
+
+//easyjson:json
+type WebhookDeliveries []WebhookDelivery
+
+// WebhookJob is a delivery claimed by a worker along with its destination.
+//
+//easyjson:skip
+type WebhookJob struct {
+	Delivery WebhookDelivery
+	Url      string
+	Secret   string
+}
@@ -8,3 +8,12 @@ func StringSliceHas(slice []string, searchedString string) bool {
 	}
 	return false
 }
+
+func Int64SliceHas(slice []int64, searchedInt int64) bool {
+	for _, i := range slice {
+		if i == searchedInt {
+			return true
+		}
+	}
+	return false
+}
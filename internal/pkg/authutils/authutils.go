@@ -0,0 +1,9 @@
+package authutils
+
+import "net/http"
+
+const NicknameHeader = "X-User-Nickname"
+
+func GetNickname(r *http.Request) string {
+	return r.Header.Get(NicknameHeader)
+}
@@ -2,8 +2,12 @@ package ioutils
 
 import (
 	"forumApp/internal/forumapp/models"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type ReadModel interface {
@@ -56,3 +60,75 @@ func WriteJSON(w http.ResponseWriter, data WriteModel) error {
 
 	return nil
 }
+
+const eventsKeepAlive = 15 * time.Second
+
+// SendEvents writes the stream as server-sent events until the client goes
+// away or the stream ends. Live events may come out of the order of ids, so
+// the ones already sent with the backlog are skipped by id.
+func SendEvents(w http.ResponseWriter, r *http.Request, stream models.EventStream) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		SendError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	sentEvents := make(map[int64]struct{}, len(stream.Backlog))
+	for _, event := range stream.Backlog {
+		if err := WriteEvent(w, event); err != nil {
+			return
+		}
+		sentEvents[event.Id] = struct{}{}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-stream.Events:
+			if !ok {
+				return
+			}
+			if _, ok := sentEvents[event.Id]; ok {
+				continue
+			}
+			if err := WriteEvent(w, event); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func WriteEvent(w io.Writer, event models.StreamEvent) error {
+	var sb strings.Builder
+	sb.WriteString("id: ")
+	sb.WriteString(strconv.FormatInt(event.Id, 10))
+	sb.WriteString("\nevent: ")
+	sb.WriteString(event.Type)
+	sb.WriteString("\n")
+	for _, line := range strings.Split(string(event.Payload), "\n") {
+		sb.WriteString("data: ")
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,33 @@
+package ioutils
+
+import (
+	"forumApp/internal/forumapp/models"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSendEventsWritesLateEvents(t *testing.T) {
+	live := make(chan models.StreamEvent, 2)
+	// the event 5 was read with the backlog too, the event 4 committed late
+	live <- models.StreamEvent{Id: 5, Type: "post.created", Payload: []byte(`{}`)}
+	live <- models.StreamEvent{Id: 4, Type: "post.created", Payload: []byte(`{}`)}
+	close(live)
+
+	stream := models.EventStream{
+		Backlog: []models.StreamEvent{{Id: 5, Type: "post.created", Payload: []byte(`{}`)}},
+		Events:  live,
+		Close:   func() {},
+	}
+
+	w := httptest.NewRecorder()
+	SendEvents(w, httptest.NewRequest("GET", "/api/thread/1/events", nil), stream)
+
+	body := w.Body.String()
+	if strings.Count(body, "id: 5\n") != 1 {
+		t.Errorf("event 5 is not sent once:\n%s", body)
+	}
+	if !strings.Contains(body, "id: 4\n") {
+		t.Errorf("late event 4 is not sent:\n%s", body)
+	}
+}